@@ -35,6 +35,7 @@ func NewClientWithSecretId(secretId, secretKey, region string) (client *Client,
 }
 
 func NewClient(credential common.CredentialIface, region string, clientProfile *profile.ClientProfile) (client *Client, err error) {
+    warnUnsupportedRegion(region, clientProfile)
     client = &Client{}
     client.Init(region).
         WithCredential(credential).
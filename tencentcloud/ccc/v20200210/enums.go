@@ -0,0 +1,226 @@
+// Copyright (c) 2017-2018 THL A29 Limited, a Tencent company. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20200210
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CallDirection is the direction of a call, see the Direction of TelCdrInfo, PSTNSession and PSTNSessionInfo
+type CallDirection int64
+
+const (
+	// CallDirectionUnknown means the direction is not returned
+	CallDirectionUnknown  CallDirection = -1
+	CallDirectionInbound  CallDirection = 0
+	CallDirectionOutbound CallDirection = 1
+)
+
+var callDirectionNames = map[int64]string{
+	int64(CallDirectionUnknown):  "Unknown",
+	int64(CallDirectionInbound):  "Inbound",
+	int64(CallDirectionOutbound): "Outbound",
+}
+
+func (d CallDirection) String() string {
+	return enumString("CallDirection", int64(d), callDirectionNames)
+}
+
+// ParseCallDirection returns the CallDirection whose String is s, ok is false if there is none
+func ParseCallDirection(s string) (d CallDirection, ok bool) {
+	value, ok := parseEnum("CallDirection", s, callDirectionNames)
+	return CallDirection(value), ok
+}
+
+// enumString returns the name of value, or typeName(value) if it has no name, e.g. a value added by the API later
+func enumString(typeName string, value int64, names map[int64]string) string {
+	if name, ok := names[value]; ok {
+		return name
+	}
+	return typeName + "(" + strconv.FormatInt(value, 10) + ")"
+}
+
+// parseEnum is the inverse of enumString
+func parseEnum(typeName, s string, names map[int64]string) (int64, bool) {
+	for value, name := range names {
+		if name == s {
+			return value, true
+		}
+	}
+	if !strings.HasPrefix(s, typeName+"(") || !strings.HasSuffix(s, ")") {
+		return 0, false
+	}
+	value, err := strconv.ParseInt(s[len(typeName)+1:len(s)-1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+func callDirection(direction *int64) CallDirection {
+	if direction == nil {
+		return CallDirectionUnknown
+	}
+	return CallDirection(*direction)
+}
+
+// CallDirection returns the typed Direction, CallDirectionUnknown if it is absent
+func (r *TelCdrInfo) CallDirection() CallDirection {
+	return callDirection(r.Direction)
+}
+
+// CallDirection returns the typed Direction, CallDirectionUnknown if it is absent
+func (r *PSTNSession) CallDirection() CallDirection {
+	return callDirection(r.Direction)
+}
+
+// CallDirection returns the typed Direction, CallDirectionUnknown if it is absent
+func (r *PSTNSessionInfo) CallDirection() CallDirection {
+	return callDirection(r.Direction)
+}
+
+// TelCdrEndStatus is the end status of a call, see TelCdrInfo.EndStatus
+type TelCdrEndStatus int64
+
+const (
+	// TelCdrEndStatusUnknown means the end status is not returned
+	TelCdrEndStatusUnknown        TelCdrEndStatus = -1
+	TelCdrEndStatusError          TelCdrEndStatus = 0
+	TelCdrEndStatusOK             TelCdrEndStatus = 1
+	TelCdrEndStatusUnconnected    TelCdrEndStatus = 2
+	TelCdrEndStatusSeatGiveUp     TelCdrEndStatus = 17
+	TelCdrEndStatusBlackList      TelCdrEndStatus = 100
+	TelCdrEndStatusSeatForward    TelCdrEndStatus = 101
+	TelCdrEndStatusIVRGiveUp      TelCdrEndStatus = 102
+	TelCdrEndStatusWaitingGiveUp  TelCdrEndStatus = 103
+	TelCdrEndStatusRingingGiveUp  TelCdrEndStatus = 104
+	TelCdrEndStatusNoSeatOnline   TelCdrEndStatus = 105
+	TelCdrEndStatusNotWorkTime    TelCdrEndStatus = 106
+	TelCdrEndStatusIVREnd         TelCdrEndStatus = 107
+	TelCdrEndStatusUnknownStatus  TelCdrEndStatus = 201
+	TelCdrEndStatusNotAnswer      TelCdrEndStatus = 202
+	TelCdrEndStatusUserReject     TelCdrEndStatus = 203
+	TelCdrEndStatusPowerOff       TelCdrEndStatus = 204
+	TelCdrEndStatusNumberNotExist TelCdrEndStatus = 205
+	TelCdrEndStatusBusy           TelCdrEndStatus = 206
+	TelCdrEndStatusOutOfCredit    TelCdrEndStatus = 207
+	TelCdrEndStatusOperatorError  TelCdrEndStatus = 208
+	TelCdrEndStatusCallerCancel   TelCdrEndStatus = 209
+	TelCdrEndStatusNotInService   TelCdrEndStatus = 210
+)
+
+var telCdrEndStatusNames = map[int64]string{
+	int64(TelCdrEndStatusUnknown):        "Unknown",
+	int64(TelCdrEndStatusError):          "Error",
+	int64(TelCdrEndStatusOK):             "OK",
+	int64(TelCdrEndStatusUnconnected):    "Unconnected",
+	int64(TelCdrEndStatusSeatGiveUp):     "SeatGiveUp",
+	int64(TelCdrEndStatusBlackList):      "BlackList",
+	int64(TelCdrEndStatusSeatForward):    "SeatForward",
+	int64(TelCdrEndStatusIVRGiveUp):      "IVRGiveUp",
+	int64(TelCdrEndStatusWaitingGiveUp):  "WaitingGiveUp",
+	int64(TelCdrEndStatusRingingGiveUp):  "RingingGiveUp",
+	int64(TelCdrEndStatusNoSeatOnline):   "NoSeatOnline",
+	int64(TelCdrEndStatusNotWorkTime):    "NotWorkTime",
+	int64(TelCdrEndStatusIVREnd):         "IVREnd",
+	int64(TelCdrEndStatusUnknownStatus):  "UnknownStatus",
+	int64(TelCdrEndStatusNotAnswer):      "NotAnswer",
+	int64(TelCdrEndStatusUserReject):     "UserReject",
+	int64(TelCdrEndStatusPowerOff):       "PowerOff",
+	int64(TelCdrEndStatusNumberNotExist): "NumberNotExist",
+	int64(TelCdrEndStatusBusy):           "Busy",
+	int64(TelCdrEndStatusOutOfCredit):    "OutOfCredit",
+	int64(TelCdrEndStatusOperatorError):  "OperatorError",
+	int64(TelCdrEndStatusCallerCancel):   "CallerCancel",
+	int64(TelCdrEndStatusNotInService):   "NotInService",
+}
+
+func (s TelCdrEndStatus) String() string {
+	return enumString("TelCdrEndStatus", int64(s), telCdrEndStatusNames)
+}
+
+// ParseTelCdrEndStatus returns the TelCdrEndStatus whose String is s, ok is false if there is none
+func ParseTelCdrEndStatus(s string) (status TelCdrEndStatus, ok bool) {
+	value, ok := parseEnum("TelCdrEndStatus", s, telCdrEndStatusNames)
+	return TelCdrEndStatus(value), ok
+}
+
+// CdrEndStatus returns the typed EndStatus, TelCdrEndStatusUnknown if it is absent
+func (r *TelCdrInfo) CdrEndStatus() TelCdrEndStatus {
+	if r.EndStatus == nil {
+		return TelCdrEndStatusUnknown
+	}
+	return TelCdrEndStatus(*r.EndStatus)
+}
+
+// SessionStatus is the status of a PSTN session, see the SessionStatus of PSTNSession and PSTNSessionInfo
+type SessionStatus string
+
+const (
+	SessionStatusRinging     SessionStatus = "ringing"
+	SessionStatusSeatJoining SessionStatus = "seatJoining"
+	SessionStatusInProgress  SessionStatus = "inProgress"
+	SessionStatusFinished    SessionStatus = "finished"
+)
+
+// Status returns the typed SessionStatus, empty if it is absent
+func (r *PSTNSession) Status() SessionStatus {
+	if r.SessionStatus == nil {
+		return ""
+	}
+	return SessionStatus(*r.SessionStatus)
+}
+
+// Status returns the typed SessionStatus, empty if it is absent
+func (r *PSTNSessionInfo) Status() SessionStatus {
+	if r.SessionStatus == nil {
+		return ""
+	}
+	return SessionStatus(*r.SessionStatus)
+}
+
+// ParticipantType is the type of a participant, see the Type and TransferToType of ServeParticipant
+type ParticipantType string
+
+const (
+	ParticipantTypeStaffSeat      ParticipantType = "staffSeat"
+	ParticipantTypeOutboundSeat   ParticipantType = "outboundSeat"
+	ParticipantTypeStaffPhoneSeat ParticipantType = "staffPhoneSeat"
+)
+
+// ParticipantType returns the typed Type, empty if it is absent
+func (r *ServeParticipant) ParticipantType() ParticipantType {
+	if r.Type == nil {
+		return ""
+	}
+	return ParticipantType(*r.Type)
+}
+
+// TransferToParticipantType returns the typed TransferToType, empty if it is absent
+func (r *ServeParticipant) TransferToParticipantType() ParticipantType {
+	if r.TransferToType == nil {
+		return ""
+	}
+	return ParticipantType(*r.TransferToType)
+}
+
+// CdrType returns the typed Type, IMCdrTypeUnknown if it is absent
+func (r *IMCdrInfo) CdrType() IMCdrType {
+	if r.Type == nil {
+		return IMCdrTypeUnknown
+	}
+	return IMCdrType(*r.Type)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2017-2018 THL A29 Limited, a Tencent company. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20200210
+
+import (
+	"testing"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+)
+
+func TestCallDirection(t *testing.T) {
+	tests := []struct {
+		direction CallDirection
+		name      string
+	}{
+		{CallDirectionUnknown, "Unknown"},
+		{CallDirectionInbound, "Inbound"},
+		{CallDirectionOutbound, "Outbound"},
+		{CallDirection(7), "CallDirection(7)"},
+	}
+	for _, test := range tests {
+		if name := test.direction.String(); name != test.name {
+			t.Fatalf("unexpected name %s, expected %s", name, test.name)
+		}
+		if direction, ok := ParseCallDirection(test.name); !ok || direction != test.direction {
+			t.Fatalf("unexpected direction %d parsed from %s", direction, test.name)
+		}
+	}
+	for _, name := range []string{"", "inbound", "CallDirection(x)", "TelCdrEndStatus(1)"} {
+		if _, ok := ParseCallDirection(name); ok {
+			t.Fatalf("%q should not be parsed", name)
+		}
+	}
+
+	if direction := (&TelCdrInfo{}).CallDirection(); direction != CallDirectionUnknown {
+		t.Fatalf("absent direction should be unknown, got %s", direction)
+	}
+	if direction := (&TelCdrInfo{Direction: common.Int64Ptr(1)}).CallDirection(); direction != CallDirectionOutbound {
+		t.Fatalf("unexpected direction %s", direction)
+	}
+}
+
+func TestTelCdrEndStatus(t *testing.T) {
+	for value := range telCdrEndStatusNames {
+		status := TelCdrEndStatus(value)
+		if parsed, ok := ParseTelCdrEndStatus(status.String()); !ok || parsed != status {
+			t.Fatalf("unexpected status %d parsed from %s", parsed, status)
+		}
+	}
+	unknown := TelCdrEndStatus(300)
+	if unknown.String() != "TelCdrEndStatus(300)" {
+		t.Fatalf("unexpected name %s", unknown)
+	}
+	if parsed, ok := ParseTelCdrEndStatus(unknown.String()); !ok || parsed != unknown {
+		t.Fatalf("unexpected status %d parsed from %s", parsed, unknown)
+	}
+	if _, ok := ParseTelCdrEndStatus("Hangup"); ok {
+		t.Fatalf("unknown name should not be parsed")
+	}
+	if status := (&TelCdrInfo{}).CdrEndStatus(); status != TelCdrEndStatusUnknown {
+		t.Fatalf("absent end status should be unknown, got %s", status)
+	}
+}
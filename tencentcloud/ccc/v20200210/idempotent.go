@@ -0,0 +1,42 @@
+// Copyright (c) 2017-2018 THL A29 Limited, a Tencent company. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20200210
+
+import (
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+)
+
+// idempotentActions are the actions of ccc 2020-02-10 which take effect once however many times they are sent,
+// so they are retried without a ClientToken. CreateStaff is not, it may create the staff twice.
+var idempotentActions = []string{
+	"DescribeChatMessages",
+	"DescribeIMCdrs",
+	"DescribePSTNActiveSessionList",
+	"DescribeSeatUserList",
+	"DescribeSkillGroupInfoList",
+	"DescribeStaffInfoList",
+	"DescribeTelCallInfo",
+	"DescribeTelCdr",
+	"DescribeTelSession",
+	"BindStaffSkillGroupList",
+	"UnbindStaffSkillGroupList",
+	"DeleteStaff",
+	"CreateSDKLoginToken",
+	"CreateUserSig",
+}
+
+func init() {
+	common.RegisterIdempotentActions("ccc", APIVersion, idempotentActions...)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2017-2018 THL A29 Limited, a Tencent company. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20200210
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/regions"
+)
+
+// unavailableRT responds 503 to the first request of each action, then succeeds
+type unavailableRT struct {
+	requests map[string]int
+}
+
+func (u *unavailableRT) RoundTrip(request *http.Request) (*http.Response, error) {
+	action := request.Header["X-TC-Action"][0]
+	u.requests[action]++
+	if u.requests[action] == 1 {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable", Header: http.Header{},
+			Body: ioutil.NopCloser(bytes.NewBufferString("unavailable"))}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{},
+		Body: ioutil.NopCloser(bytes.NewBufferString(`{"Response": {"RequestId": "req"}}`))}, nil
+}
+
+func TestIdempotentActionsRetry(t *testing.T) {
+	send := func(retryWithoutClientToken bool, request tchttp.Request) (int, error) {
+		prof := profile.NewClientProfile()
+		prof.NetworkFailureMaxRetries = 1
+		prof.NetworkFailureRetryDuration = profile.ConstantDurationFunc(0)
+		prof.RetryWithoutClientToken = retryWithoutClientToken
+		client, err := NewClient(common.NewCredential("id", "key"), regions.Guangzhou, prof)
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		rt := &unavailableRT{requests: map[string]int{}}
+		client.WithHttpTransport(rt)
+		err = client.Send(request, tchttp.NewCommonResponse())
+		return rt.requests[request.GetAction()], err
+	}
+
+	createStaff := func() tchttp.Request {
+		request := NewCreateStaffRequest()
+		request.SdkAppId = common.Int64Ptr(1400000000)
+		request.Staffs = []*SeatUserInfo{{Name: common.StringPtr("staff"), Mail: common.StringPtr("staff@example.com")}}
+		return request
+	}
+	// CreateStaff has no ClientToken, it may create the staff twice if retried
+	if n, err := send(false, createStaff()); err == nil || n != 1 {
+		t.Fatalf("CreateStaff should not be retried, sent %d times, %+v", n, err)
+	}
+	if n, err := send(true, createStaff()); err != nil || n != 2 {
+		t.Fatalf("CreateStaff should be retried when opted in, sent %d times, %+v", n, err)
+	}
+
+	for _, request := range []tchttp.Request{NewDescribeTelCdrRequest(), NewDescribeStaffInfoListRequest(), NewDeleteStaffRequest()} {
+		if n, err := send(false, request); err != nil || n != 2 {
+			t.Fatalf("%s should be retried, sent %d times, %+v", request.GetAction(), n, err)
+		}
+	}
+}
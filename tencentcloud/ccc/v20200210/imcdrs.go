@@ -0,0 +1,135 @@
+// Copyright (c) 2017-2018 THL A29 Limited, a Tencent company. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20200210
+
+import (
+	"context"
+	"time"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+)
+
+// maxIMCdrsLimit is the max Limit accepted by DescribeIMCdrs
+const maxIMCdrsLimit = 100
+
+// IMCdrType is the service type of an IM cdr, see IMCdrInfo.Type
+type IMCdrType int64
+
+const (
+	// IMCdrTypeUnknown means the type is not returned
+	IMCdrTypeUnknown IMCdrType = 0
+	// IMCdrTypeFullMedia is the full media service
+	IMCdrTypeFullMedia IMCdrType = 1
+	// IMCdrTypeText is the text customer service
+	IMCdrTypeText IMCdrType = 2
+)
+
+func (t IMCdrType) String() string {
+	switch t {
+	case IMCdrTypeFullMedia:
+		return "FullMedia"
+	case IMCdrTypeText:
+		return "Text"
+	default:
+		return "Unknown"
+	}
+}
+
+// IMCdrRecord is an IMCdrInfo whose optional fields are resolved to zero values if absent
+type IMCdrRecord struct {
+	// Id of the service record
+	Id string
+	// Duration of the service
+	Duration time.Duration
+	// EndStatus of the service
+	EndStatus int64
+	// Nickname of the user
+	Nickname string
+	// Type of the service, full media or text
+	Type IMCdrType
+	// StaffId of the staff serving the user
+	StaffId string
+	// Time of the service, zero if absent
+	Time time.Time
+}
+
+// NewIMCdrRecord converts info to an IMCdrRecord
+func NewIMCdrRecord(info *IMCdrInfo) IMCdrRecord {
+	record := IMCdrRecord{
+		Time: common.UnixTime(info.Timestamp),
+	}
+	if info.Id != nil {
+		record.Id = *info.Id
+	}
+	if info.Duration != nil {
+		record.Duration = time.Duration(*info.Duration) * time.Second
+	}
+	if info.EndStatus != nil {
+		record.EndStatus = *info.EndStatus
+	}
+	if info.Nickname != nil {
+		record.Nickname = *info.Nickname
+	}
+	if info.Type != nil {
+		record.Type = IMCdrType(*info.Type)
+	}
+	if info.StaffId != nil {
+		record.StaffId = *info.StaffId
+	}
+	return record
+}
+
+// GetIMCdrRecords pages through DescribeIMCdrs and returns all the records matching
+// StartTimestamp, EndTimestamp, InstanceId, SdkAppId and Type of request.
+// Type filters the full media (IMCdrTypeFullMedia) or the text (IMCdrTypeText) services, nil means both.
+// Limit of request is used as the page size, which defaults to 100, Offset is ignored.
+func (c *Client) GetIMCdrRecords(ctx context.Context, request *DescribeIMCdrsRequest) (records []IMCdrRecord, err error) {
+	if request == nil {
+		request = NewDescribeIMCdrsRequest()
+	}
+	limit := int64(maxIMCdrsLimit)
+	if request.Limit != nil && *request.Limit > 0 && *request.Limit < limit {
+		limit = *request.Limit
+	}
+
+	fetch := func(ctx context.Context, offset, limit int64) (count, total int64, err error) {
+		page := NewDescribeIMCdrsRequest()
+		page.SetContext(ctx)
+		page.StartTimestamp = request.StartTimestamp
+		page.EndTimestamp = request.EndTimestamp
+		page.InstanceId = request.InstanceId
+		page.SdkAppId = request.SdkAppId
+		page.Type = request.Type
+		page.Limit = common.Int64Ptr(limit)
+		page.Offset = common.Int64Ptr(offset)
+		response, err := c.DescribeIMCdrs(page)
+		if err != nil {
+			return 0, 0, err
+		}
+		if response.Response.TotalCount != nil {
+			total = *response.Response.TotalCount
+		}
+		for _, info := range response.Response.IMCdrs {
+			if info != nil {
+				records = append(records, NewIMCdrRecord(info))
+			}
+		}
+		return int64(len(response.Response.IMCdrs)), total, nil
+	}
+	if err = common.NewPaginator(limit, fetch).Run(ctx); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2017-2018 THL A29 Limited, a Tencent company. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20200210
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+func TestGetIMCdrRecords(t *testing.T) {
+	const total = 5
+	var pages []string
+	client, _ := newMockClient(t, func(action string, params map[string]interface{}) string {
+		offset, limit := int64(params["Offset"].(float64)), int64(params["Limit"].(float64))
+		pages = append(pages, fmt.Sprintf("%d/%d/%v", offset, limit, params["Type"]))
+		var records []string
+		for i := offset; i < offset+limit && i < total; i++ {
+			records = append(records, fmt.Sprintf(`{"Id": "cdr-%d", "Duration": %d, "Type": 2, "Timestamp": %d}`, i, i*10, 1600000000+i))
+		}
+		return fmt.Sprintf(`{"TotalCount": %d, "IMCdrs": [%s], "RequestId": "req"}`, total, strings.Join(records, ","))
+	})
+
+	request := NewDescribeIMCdrsRequest()
+	request.SdkAppId = common.Int64Ptr(1400000000)
+	request.Type = common.Int64Ptr(int64(IMCdrTypeText))
+	request.Limit = common.Int64Ptr(2)
+	records, err := client.GetIMCdrRecords(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if fmt.Sprint(pages) != "[0/2/2 2/2/2 4/2/2]" {
+		t.Fatalf("unexpected pages %v", pages)
+	}
+	if len(records) != total {
+		t.Fatalf("unexpected %d records", len(records))
+	}
+	for i, record := range records {
+		if record.Id != fmt.Sprintf("cdr-%d", i) || record.Duration != time.Duration(i*10)*time.Second ||
+			record.Type != IMCdrTypeText || record.Time.Unix() != int64(1600000000+i) {
+			t.Fatalf("unexpected record %d: %+v", i, record)
+		}
+	}
+}
+
+func TestGetIMCdrRecordsError(t *testing.T) {
+	client, rt := newMockClient(t, func(action string, params map[string]interface{}) string {
+		if params["Offset"].(float64) > 0 {
+			return `{"Error": {"Code": "InternalError", "Message": "failed"}, "RequestId": "req"}`
+		}
+		return `{"TotalCount": 200, "IMCdrs": [{"Id": "cdr-0"}], "RequestId": "req"}`
+	})
+	records, err := client.GetIMCdrRecords(context.Background(), nil)
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "InternalError" || records != nil || rt.sent() != 2 {
+		t.Fatalf("unexpected error %+v with %d records", err, len(records))
+	}
+}
+
+func TestNewIMCdrRecord(t *testing.T) {
+	record := NewIMCdrRecord(&IMCdrInfo{})
+	if record.Id != "" || record.Duration != 0 || record.Type != IMCdrTypeUnknown || !record.Time.IsZero() {
+		t.Fatalf("absent fields should be zero, got %+v", record)
+	}
+	if record.Type.String() != "Unknown" || IMCdrTypeFullMedia.String() != "FullMedia" || IMCdrTypeText.String() != "Text" {
+		t.Fatalf("unexpected type names")
+	}
+}
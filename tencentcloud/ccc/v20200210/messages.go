@@ -0,0 +1,100 @@
+// Copyright (c) 2017-2018 THL A29 Limited, a Tencent company. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20200210
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+)
+
+// maxChatMessagesLimit is the max Limit accepted by DescribeChatMessages
+const maxChatMessagesLimit = 100
+
+// TimeRange is the range [Start, End) of time, a zero Start or End means unbounded
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains reports whether t is within the range
+func (r TimeRange) Contains(t time.Time) bool {
+	return (r.Start.IsZero() || !t.Before(r.Start)) && (r.End.IsZero() || t.Before(r.End))
+}
+
+// GetAllChatMessages pages through DescribeChatMessages and returns all the messages of the session sessionId,
+// i.e. the CdrId of the service record, of the application sdkAppId, sorted by timestamp ascending.
+//
+// Only the messages sent within timeRange are returned.
+// The messages returned repeatedly by overlapping pages are deduplicated.
+func (c *Client) GetAllChatMessages(ctx context.Context, sdkAppId int64, sessionId string, timeRange TimeRange) (messages []*MessageBody, err error) {
+	seen := make(map[string]bool)
+	fetch := func(ctx context.Context, offset, limit int64) (count, total int64, err error) {
+		page := NewDescribeChatMessagesRequest()
+		page.SetContext(ctx)
+		page.CdrId = common.StringPtr(sessionId)
+		page.SdkAppId = common.Int64Ptr(sdkAppId)
+		page.Limit = common.Int64Ptr(limit)
+		page.Offset = common.Int64Ptr(offset)
+		page.Order = common.Int64Ptr(1)
+		response, err := c.DescribeChatMessages(page)
+		if err != nil {
+			return 0, 0, err
+		}
+		if response.Response.TotalCount != nil {
+			total = *response.Response.TotalCount
+		}
+		for _, message := range response.Response.Messages {
+			if message == nil {
+				continue
+			}
+			key := chatMessageKey(message)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if !timeRange.Contains(common.UnixTime(message.Timestamp)) {
+				continue
+			}
+			messages = append(messages, message)
+		}
+		return int64(len(response.Response.Messages)), total, nil
+	}
+	if err = common.NewPaginator(maxChatMessagesLimit, fetch).Run(ctx); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		return chatMessageTimestamp(messages[i]) < chatMessageTimestamp(messages[j])
+	})
+	return messages, nil
+}
+
+// chatMessageKey identifies a message, since messages have no id,
+// the timestamp, the sender and the content are used together.
+func chatMessageKey(message *MessageBody) string {
+	b, _ := json.Marshal(message)
+	return string(b)
+}
+
+func chatMessageTimestamp(message *MessageBody) int64 {
+	if message.Timestamp == nil {
+		return 0
+	}
+	return *message.Timestamp
+}
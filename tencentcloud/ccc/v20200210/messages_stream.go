@@ -0,0 +1,165 @@
+// Copyright (c) 2017-2018 THL A29 Limited, a Tencent company. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20200210
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// DescribeChatMessagesStream calls DescribeChatMessages and decodes Response.Messages of the body incrementally,
+// fn is called with each message as soon as it is decoded, so the memory used is bounded by one message
+// rather than the whole response. The error returned by fn stops the decoding and is returned as it is.
+// The body is always closed when it returns.
+//
+// Since the body is not buffered, the request is not retried on the API errors, e.g. RequestLimitExceeded,
+// which are still returned as *errors.TencentCloudSDKError.
+func (c *Client) DescribeChatMessagesStream(ctx context.Context, request *DescribeChatMessagesRequest, fn func(message *MessageBody) error) error {
+	if request == nil {
+		request = NewDescribeChatMessagesRequest()
+	}
+	request.SetContext(ctx)
+	response := tchttp.NewStreamResponse()
+	if err := c.Send(request, response); err != nil {
+		return err
+	}
+	body := response.GetStreamBody()
+	if body == nil {
+		return nil
+	}
+	defer body.Close()
+	return decodeChatMessages(json.NewDecoder(body), response.GetRequestId(), fn)
+}
+
+// decodeChatMessages walks {"Response": {"Messages": [...], "Error": {...}, "RequestId": "..."}}
+func decodeChatMessages(dec *json.Decoder, requestId string, fn func(message *MessageBody) error) error {
+	parseError := func(err error) error {
+		msg := fmt.Sprintf("Fail to parse json content of DescribeChatMessages, because: %s", err)
+		return tcerr.NewTencentCloudSDKErrorWithCause("ClientError.ParseJsonError", msg, requestId, err)
+	}
+	var apiErr struct {
+		Code    string `json:"Code"`
+		Message string `json:"Message"`
+	}
+	err := decodeObject(dec, func(key string) error {
+		if key != "Response" {
+			return skipValue(dec)
+		}
+		return decodeObject(dec, func(key string) error {
+			switch key {
+			case "Messages":
+				return decodeArray(dec, func() error {
+					message := &MessageBody{}
+					if err := dec.Decode(message); err != nil {
+						return err
+					}
+					return callbackError{fn(message)}.orNil()
+				})
+			case "Error":
+				return dec.Decode(&apiErr)
+			case "RequestId":
+				return dec.Decode(&requestId)
+			}
+			return skipValue(dec)
+		})
+	})
+	if cbErr, ok := err.(callbackError); ok {
+		return cbErr.err
+	}
+	if err != nil {
+		return parseError(err)
+	}
+	if apiErr.Code != "" {
+		return tcerr.NewTencentCloudSDKError(apiErr.Code, apiErr.Message, requestId)
+	}
+	return nil
+}
+
+// callbackError wraps the error returned by the callback, so it is told from the decoding errors
+type callbackError struct {
+	err error
+}
+
+func (e callbackError) Error() string {
+	return e.err.Error()
+}
+
+func (e callbackError) orNil() error {
+	if e.err == nil {
+		return nil
+	}
+	return e
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := token.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %s, got %v", delim, token)
+	}
+	return nil
+}
+
+// decodeObject calls fn with each key of the object, fn must consume the value of the key
+func decodeObject(dec *json.Decoder, fn func(key string) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("expected object key, got %v", token)
+		}
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+// decodeArray calls fn for each element of the array, fn must consume the element
+func decodeArray(dec *json.Decoder, fn func() error) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+	if d, ok := token.(json.Delim); !ok || d != '[' {
+		return fmt.Errorf("expected [, got %v", token)
+	}
+	for dec.More() {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+func skipValue(dec *json.Decoder) error {
+	var value json.RawMessage
+	return dec.Decode(&value)
+}
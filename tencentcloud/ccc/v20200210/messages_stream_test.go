@@ -0,0 +1,128 @@
+// Copyright (c) 2017-2018 THL A29 Limited, a Tencent company. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20200210
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+// chatMessagesJSON returns the Response object of DescribeChatMessages with a message for each of the timestamps
+func chatMessagesJSON(timestamps ...int64) string {
+	messages := make([]string, len(timestamps))
+	for i, ts := range timestamps {
+		messages[i] = fmt.Sprintf(`{"Timestamp": %d, "From": "user", "Messages": [{"Type": "text", "Content": "hello %d"}]}`, ts, ts)
+	}
+	return fmt.Sprintf(`{"Messages": [%s], "RequestId": "req"}`, strings.Join(messages, ","))
+}
+
+func TestDescribeChatMessagesStream(t *testing.T) {
+	client, rt := newMockClient(t, func(action string, params map[string]interface{}) string {
+		// two messages per page
+		offset := int64(params["Offset"].(float64))
+		if offset >= 4 {
+			return chatMessagesJSON()
+		}
+		return chatMessagesJSON(offset+1, offset+2)
+	})
+
+	var timestamps []int64
+	for offset := int64(0); ; offset += 2 {
+		request := NewDescribeChatMessagesRequest()
+		request.Offset = common.Int64Ptr(offset)
+		request.Limit = common.Int64Ptr(2)
+		count := 0
+		err := client.DescribeChatMessagesStream(context.Background(), request, func(message *MessageBody) error {
+			count++
+			timestamps = append(timestamps, *message.Timestamp)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if count == 0 {
+			break
+		}
+	}
+	if fmt.Sprint(timestamps) != "[1 2 3 4]" || rt.sent() != 3 || rt.closedBodies() != 3 {
+		t.Fatalf("unexpected messages %v of %d pages, %d bodies closed", timestamps, rt.sent(), rt.closedBodies())
+	}
+}
+
+func TestDescribeChatMessagesStreamCallbackError(t *testing.T) {
+	client, rt := newMockClient(t, func(action string, params map[string]interface{}) string {
+		return chatMessagesJSON(1, 2, 3, 4)
+	})
+
+	errStop := errors.New("stop")
+	var timestamps []int64
+	err := client.DescribeChatMessagesStream(context.Background(), nil, func(message *MessageBody) error {
+		timestamps = append(timestamps, *message.Timestamp)
+		if *message.Timestamp == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Fatalf("the error of the callback should be returned as it is, got %+v", err)
+	}
+	if fmt.Sprint(timestamps) != "[1 2]" || rt.closedBodies() != 1 {
+		t.Fatalf("decoding should stop at the error, got %v, %d bodies closed", timestamps, rt.closedBodies())
+	}
+}
+
+func TestDecodeChatMessages(t *testing.T) {
+	// the body is read a byte at a time, as it arrives from a slow connection
+	body := `{"Response": ` + chatMessagesJSON(1, 2, 3) + `}`
+	var timestamps []int64
+	fn := func(message *MessageBody) error {
+		timestamps = append(timestamps, *message.Timestamp)
+		return nil
+	}
+	if err := decodeChatMessages(json.NewDecoder(iotest.OneByteReader(strings.NewReader(body))), "", fn); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if fmt.Sprint(timestamps) != "[1 2 3]" {
+		t.Fatalf("unexpected messages %v", timestamps)
+	}
+
+	// the messages before the malformed one are still delivered
+	timestamps = nil
+	body = `{"Response": {"Messages": [{"Timestamp": 1}, {"Timestamp": "x"}], "RequestId": "req"}}`
+	err := decodeChatMessages(json.NewDecoder(strings.NewReader(body)), "req-header", fn)
+	sdkErr, ok := err.(*tcerr.TencentCloudSDKError)
+	if !ok || sdkErr.GetCode() != "ClientError.ParseJsonError" || sdkErr.GetRequestId() != "req-header" || fmt.Sprint(timestamps) != "[1]" {
+		t.Fatalf("unexpected error %+v with messages %v", err, timestamps)
+	}
+
+	body = `{"Response": {"Messages": [{"Timestamp": 1}`
+	if err = decodeChatMessages(json.NewDecoder(strings.NewReader(body)), "", fn); err == nil {
+		t.Fatalf("expected error for the truncated body")
+	}
+
+	body = `{"Response": {"Error": {"Code": "ResourceNotFound", "Message": "not found"}, "RequestId": "req-1"}}`
+	err = decodeChatMessages(json.NewDecoder(strings.NewReader(body)), "", fn)
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ResourceNotFound" || sdkErr.GetRequestId() != "req-1" {
+		t.Fatalf("unexpected error %+v", err)
+	}
+}
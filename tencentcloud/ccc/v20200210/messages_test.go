@@ -0,0 +1,78 @@
+// Copyright (c) 2017-2018 THL A29 Limited, a Tencent company. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20200210
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestGetAllChatMessages(t *testing.T) {
+	pages := map[int64][]int64{0: {3, 1}, 2: {2, 5}, 4: {5, 4}}
+	client, rt := newMockClient(t, func(action string, params map[string]interface{}) string {
+		if action != "DescribeChatMessages" || params["CdrId"] != "session-1" || params["SdkAppId"].(float64) != 1400000000 || params["Order"].(float64) != 1 {
+			t.Errorf("unexpected request %s %v", action, params)
+		}
+		var messages string
+		for i, ts := range pages[int64(params["Offset"].(float64))] {
+			if i > 0 {
+				messages += ","
+			}
+			messages += fmt.Sprintf(`{"Timestamp": %d, "From": "user", "Messages": [{"Type": "text", "Content": "hello %d"}]}`, ts, ts)
+		}
+		return fmt.Sprintf(`{"TotalCount": 6, "Messages": [%s], "RequestId": "req"}`, messages)
+	})
+
+	// the message 5 is returned by two overlapping pages, and excluded by the range as 1 is
+	messages, err := client.GetAllChatMessages(context.Background(), 1400000000, "session-1", TimeRange{Start: time.Unix(2, 0), End: time.Unix(5, 0)})
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	timestamps := make([]int64, len(messages))
+	for i, message := range messages {
+		timestamps[i] = *message.Timestamp
+	}
+	if fmt.Sprint(timestamps) != "[2 3 4]" || rt.sent() != 3 {
+		t.Fatalf("unexpected messages %v of %d pages", timestamps, rt.sent())
+	}
+
+	messages, err = client.GetAllChatMessages(context.Background(), 1400000000, "session-1", TimeRange{})
+	if err != nil || len(messages) != 5 {
+		t.Fatalf("unexpected %d messages, %+v", len(messages), err)
+	}
+}
+
+func TestTimeRangeContains(t *testing.T) {
+	start, end := time.Unix(10, 0), time.Unix(20, 0)
+	tests := []struct {
+		r        TimeRange
+		t        time.Time
+		expected bool
+	}{
+		{TimeRange{}, time.Unix(0, 0), true},
+		{TimeRange{Start: start, End: end}, start, true},
+		{TimeRange{Start: start, End: end}, end, false},
+		{TimeRange{Start: start, End: end}, time.Unix(9, 0), false},
+		{TimeRange{Start: start}, time.Unix(100, 0), true},
+		{TimeRange{End: end}, time.Unix(0, 0), true},
+	}
+	for i, test := range tests {
+		if actual := test.r.Contains(test.t); actual != test.expected {
+			t.Fatalf("case %d: expected %v, got %v", i, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2017-2018 THL A29 Limited, a Tencent company. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20200210
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/regions"
+)
+
+// mockRT answers the requests with handle, which returns the json of the Response object of the action
+type mockRT struct {
+	mu       sync.Mutex
+	requests []string
+	closed   int
+	handle   func(action string, params map[string]interface{}) string
+}
+
+// mockBody counts the bodies closed by the client or the caller
+type mockBody struct {
+	*bytes.Reader
+	rt *mockRT
+}
+
+func (b *mockBody) Close() error {
+	b.rt.mu.Lock()
+	b.rt.closed++
+	b.rt.mu.Unlock()
+	return nil
+}
+
+func (m *mockRT) RoundTrip(request *http.Request) (*http.Response, error) {
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]interface{}{}
+	if err := json.Unmarshal(body, &params); err != nil {
+		return nil, err
+	}
+	action := request.Header["X-TC-Action"][0]
+	m.mu.Lock()
+	m.requests = append(m.requests, action)
+	m.mu.Unlock()
+	response := `{"Response": ` + m.handle(action, params) + `}`
+	responseBody := &mockBody{Reader: bytes.NewReader([]byte(response)), rt: m}
+	return &http.Response{StatusCode: 200, Header: http.Header{}, Body: responseBody}, nil
+}
+
+// sent returns the number of the requests sent
+func (m *mockRT) sent() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.requests)
+}
+
+// closedBodies returns the number of the response bodies closed
+func (m *mockRT) closedBodies() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+func newMockClient(t *testing.T, handle func(action string, params map[string]interface{}) string) (*Client, *mockRT) {
+	client, err := NewClient(common.NewCredential("id", "key"), regions.Guangzhou, profile.NewClientProfile())
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	rt := &mockRT{handle: handle}
+	client.WithHttpTransport(rt)
+	return client, rt
+}
@@ -137,10 +137,10 @@ type CreateStaffRequest struct {
 	*tchttp.BaseRequest
 
 	// 应用ID
-	SdkAppId *int64 `json:"SdkAppId,omitempty" name:"SdkAppId"`
+	SdkAppId *int64 `json:"SdkAppId,omitempty" name:"SdkAppId" validate:"required"`
 
 	// 客服信息，个数不超过 10
-	Staffs []*SeatUserInfo `json:"Staffs,omitempty" name:"Staffs"`
+	Staffs []*SeatUserInfo `json:"Staffs,omitempty" name:"Staffs" validate:"required,maxlen=10"`
 }
 
 func (r *CreateStaffRequest) ToJsonString() string {
@@ -197,7 +197,7 @@ type CreateUserSigRequest struct {
 	Uid *string `json:"Uid,omitempty" name:"Uid"`
 
 	// 有效期，单位秒，不超过 1 小时
-	ExpiredTime *int64 `json:"ExpiredTime,omitempty" name:"ExpiredTime"`
+	ExpiredTime *int64 `json:"ExpiredTime,omitempty" name:"ExpiredTime" validate:"min=0,max=3600"`
 
 	// 用户签名数据
 	ClientData *string `json:"ClientData,omitempty" name:"ClientData"`
@@ -321,7 +321,7 @@ type DescribeChatMessagesRequest struct {
 	Offset *int64 `json:"Offset,omitempty" name:"Offset"`
 
 	// 1为从早到晚，2为从晚到早，默认为2
-	Order *int64 `json:"Order,omitempty" name:"Order"`
+	Order *int64 `json:"Order,omitempty" name:"Order" validate:"enum=1|2"`
 }
 
 func (r *DescribeChatMessagesRequest) ToJsonString() string {
@@ -577,10 +577,10 @@ type DescribeSkillGroupInfoListRequest struct {
 	SdkAppId *int64 `json:"SdkAppId,omitempty" name:"SdkAppId"`
 
 	// 分页尺寸，上限 100
-	PageSize *int64 `json:"PageSize,omitempty" name:"PageSize"`
+	PageSize *int64 `json:"PageSize,omitempty" name:"PageSize" validate:"min=1,max=100"`
 
 	// 分页页码，从 0 开始
-	PageNumber *int64 `json:"PageNumber,omitempty" name:"PageNumber"`
+	PageNumber *int64 `json:"PageNumber,omitempty" name:"PageNumber" validate:"min=0"`
 
 	// 技能组ID，查询单个技能组时使用
 	SkillGroupId *int64 `json:"SkillGroupId,omitempty" name:"SkillGroupId"`
@@ -645,10 +645,10 @@ type DescribeStaffInfoListRequest struct {
 	SdkAppId *int64 `json:"SdkAppId,omitempty" name:"SdkAppId"`
 
 	// 分页尺寸，上限 100
-	PageSize *int64 `json:"PageSize,omitempty" name:"PageSize"`
+	PageSize *int64 `json:"PageSize,omitempty" name:"PageSize" validate:"min=1,max=100"`
 
 	// 分页页码，从 0 开始
-	PageNumber *int64 `json:"PageNumber,omitempty" name:"PageNumber"`
+	PageNumber *int64 `json:"PageNumber,omitempty" name:"PageNumber" validate:"min=0"`
 
 	// 坐席账号，查询单个坐席时使用
 	StaffMail *string `json:"StaffMail,omitempty" name:"StaffMail"`
@@ -791,10 +791,10 @@ type DescribeTelCdrRequest struct {
 	SdkAppId *int64 `json:"SdkAppId,omitempty" name:"SdkAppId"`
 
 	// 分页尺寸，上限 100
-	PageSize *int64 `json:"PageSize,omitempty" name:"PageSize"`
+	PageSize *int64 `json:"PageSize,omitempty" name:"PageSize" validate:"min=1,max=100"`
 
 	// 分页页码，从 0 开始
-	PageNumber *int64 `json:"PageNumber,omitempty" name:"PageNumber"`
+	PageNumber *int64 `json:"PageNumber,omitempty" name:"PageNumber" validate:"min=0"`
 
 	// 按手机号筛选
 	Phones []*string `json:"Phones,omitempty" name:"Phones"`
@@ -1062,10 +1062,10 @@ type SeatUserInfo struct {
 	Name *string `json:"Name,omitempty" name:"Name"`
 
 	// 坐席邮箱
-	Mail *string `json:"Mail,omitempty" name:"Mail"`
+	Mail *string `json:"Mail,omitempty" name:"Mail" validate:"format=email"`
 
 	// 坐席电话号码（带0086前缀）
-	Phone *string `json:"Phone,omitempty" name:"Phone"`
+	Phone *string `json:"Phone,omitempty" name:"Phone" validate:"format=phone"`
 
 	// 坐席昵称
 	Nick *string `json:"Nick,omitempty" name:"Nick"`
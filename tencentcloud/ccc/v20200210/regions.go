@@ -0,0 +1,58 @@
+// Copyright (c) 2017-2018 THL A29 Limited, a Tencent company. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20200210
+
+import (
+	"log"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/regions"
+)
+
+// supportedRegions is the region list of the API documentation of ccc 2020-02-10
+var supportedRegions = []string{
+	regions.Guangzhou,
+	regions.Singapore,
+}
+
+// SupportedRegions returns the regions which ccc is available in,
+// so the region of the configuration can be validated before building a client
+func SupportedRegions() []string {
+	return append([]string(nil), supportedRegions...)
+}
+
+// IsRegionSupported reports whether ccc is available in region, e.g. it is false for the typo ap-guangzhou1
+func IsRegionSupported(region string) bool {
+	for _, r := range supportedRegions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
+// warnUnsupportedRegion logs a warning in debug mode if region is not supported,
+// the request to such a region usually fails with a confusing network error
+func warnUnsupportedRegion(region string, clientProfile *profile.ClientProfile) {
+	if region != "" && clientProfile != nil && clientProfile.Debug && !IsRegionSupported(region) {
+		log.Printf("[WARN] region %q is not one of the regions supported by ccc: %v", region, supportedRegions)
+	}
+}
+
+func init() {
+	// ccc is region scoped, the requests without a region are rejected locally
+	common.RegisterRegionalService("ccc")
+}
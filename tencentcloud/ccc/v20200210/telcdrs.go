@@ -0,0 +1,92 @@
+// Copyright (c) 2017-2018 THL A29 Limited, a Tencent company. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20200210
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+)
+
+// maxTelCdrPageSize is the max PageSize accepted by DescribeTelCdr
+const maxTelCdrPageSize = 100
+
+// AppTelCdr is a TelCdrInfo along with the SdkAppId it belongs to
+type AppTelCdr struct {
+	SdkAppId uint64
+	*TelCdrInfo
+}
+
+// DescribeTelCdrMulti pages through DescribeTelCdr for each of appIds, with at most concurrency
+// app ids fetched at the same time, and returns the records of [start, end] of all of them,
+// sorted by the start time ascending. A concurrency not positive means all the app ids at once.
+//
+// If some app ids fail, the records of the others are still returned along with a *common.BatchError,
+// whose Errors is aligned with appIds, none of the records of a failed app id is returned.
+func (c *Client) DescribeTelCdrMulti(ctx context.Context, appIds []uint64, start, end time.Time, concurrency int) (cdrs []AppTelCdr, err error) {
+	results := make([][]*TelCdrInfo, len(appIds))
+	err = common.ForEach(ctx, len(appIds), concurrency, false, func(ctx context.Context, i int) error {
+		records, err := c.getTelCdrs(ctx, appIds[i], start, end)
+		if err != nil {
+			return err
+		}
+		results[i] = records
+		return nil
+	})
+	for i, records := range results {
+		for _, record := range records {
+			cdrs = append(cdrs, AppTelCdr{SdkAppId: appIds[i], TelCdrInfo: record})
+		}
+	}
+	sort.SliceStable(cdrs, func(i, j int) bool {
+		return cdrs[i].StartTime().Before(cdrs[j].StartTime())
+	})
+	return cdrs, err
+}
+
+// getTelCdrs pages through DescribeTelCdr and returns all the records of sdkAppId within [start, end]
+func (c *Client) getTelCdrs(ctx context.Context, sdkAppId uint64, start, end time.Time) (records []*TelCdrInfo, err error) {
+	// the pages are numbered from 0, a short page which is not the last one does not shift the later pages
+	var pageNumber int64
+	fetch := func(ctx context.Context, offset, limit int64) (count, total int64, err error) {
+		page := NewDescribeTelCdrRequest()
+		page.SetContext(ctx)
+		page.SdkAppId = common.Int64Ptr(int64(sdkAppId))
+		page.StartTimeStamp = common.Int64Ptr(start.Unix())
+		page.EndTimeStamp = common.Int64Ptr(end.Unix())
+		page.PageSize = common.Int64Ptr(limit)
+		page.PageNumber = common.Int64Ptr(pageNumber)
+		pageNumber++
+		response, err := c.DescribeTelCdr(page)
+		if err != nil {
+			return 0, 0, err
+		}
+		if response.Response.TotalCount != nil {
+			total = *response.Response.TotalCount
+		}
+		for _, info := range response.Response.TelCdrs {
+			if info != nil {
+				records = append(records, info)
+			}
+		}
+		return int64(len(response.Response.TelCdrs)), total, nil
+	}
+	if err = common.NewPaginator(maxTelCdrPageSize, fetch).Run(ctx); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2017-2018 THL A29 Limited, a Tencent company. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20200210
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+)
+
+// telCdrsJSON returns a page of DescribeTelCdr with a record for each of the start timestamps
+func telCdrsJSON(total int, starts ...int64) string {
+	records := make([]string, len(starts))
+	for i, start := range starts {
+		records[i] = fmt.Sprintf(`{"StartTimestamp": %d}`, start)
+	}
+	return fmt.Sprintf(`{"TotalCount": %d, "TelCdrs": [%s], "RequestId": "req"}`, total, strings.Join(records, ","))
+}
+
+func TestDescribeTelCdrMulti(t *testing.T) {
+	var inflight, maxInflight int32
+	client, _ := newMockClient(t, func(action string, params map[string]interface{}) string {
+		n := atomic.AddInt32(&inflight, 1)
+		defer atomic.AddInt32(&inflight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInflight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInflight, max, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		switch appId := int64(params["SdkAppId"].(float64)); appId {
+		case 3:
+			return `{"Error": {"Code": "FailedOperation", "Message": "failed"}, "RequestId": "req"}`
+		default:
+			return telCdrsJSON(2, 100+appId, 10+appId)
+		}
+	})
+
+	start, end := time.Unix(0, 0), time.Unix(1000, 0)
+	cdrs, err := client.DescribeTelCdrMulti(context.Background(), []uint64{1, 2, 3, 4, 5}, start, end, 2)
+	if n := atomic.LoadInt32(&maxInflight); n != 2 {
+		t.Fatalf("unexpected concurrency %d, expected 2", n)
+	}
+	batchErr, ok := err.(*common.BatchError)
+	if !ok || len(batchErr.Errors) != 5 {
+		t.Fatalf("unexpected error %+v", err)
+	}
+	for i, err := range batchErr.Errors {
+		if (i == 2) != (err != nil) {
+			t.Fatalf("unexpected error of app %d: %+v", i+1, err)
+		}
+	}
+
+	// the records of the other apps are returned, sorted by the start time
+	expected := []string{"1:11", "2:12", "4:14", "5:15", "1:101", "2:102", "4:104", "5:105"}
+	actual := make([]string, len(cdrs))
+	for i, cdr := range cdrs {
+		actual[i] = fmt.Sprintf("%d:%d", cdr.SdkAppId, cdr.StartTime().Unix())
+	}
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Fatalf("unexpected records %v, expected %v", actual, expected)
+	}
+}
+
+func TestDescribeTelCdrMultiShortPage(t *testing.T) {
+	var mu sync.Mutex
+	var pages []int64
+	client, rt := newMockClient(t, func(action string, params map[string]interface{}) string {
+		page := int64(params["PageNumber"].(float64))
+		mu.Lock()
+		pages = append(pages, page)
+		mu.Unlock()
+		// the first page is short although it is not the last one
+		if page == 0 {
+			return telCdrsJSON(5, 1, 2)
+		}
+		return telCdrsJSON(5, 3, 4, 5)
+	})
+
+	cdrs, err := client.DescribeTelCdrMulti(context.Background(), []uint64{1}, time.Unix(0, 0), time.Unix(1000, 0), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if len(cdrs) != 5 || rt.sent() != 2 || fmt.Sprint(pages) != "[0 1]" {
+		t.Fatalf("unexpected %d records of pages %v", len(cdrs), pages)
+	}
+	for i, cdr := range cdrs {
+		if cdr.StartTime().Unix() != int64(i+1) {
+			t.Fatalf("unexpected record %d: %d", i, cdr.StartTime().Unix())
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2017-2018 THL A29 Limited, a Tencent company. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20200210
+
+import (
+	"time"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+)
+
+// CallTime returns the time when the call was initiated, see Time
+func (r *TelCdrInfo) CallTime() time.Time {
+	return common.UnixTime(r.Time)
+}
+
+// StartTime returns the time when the session started, see StartTimestamp
+func (r *TelCdrInfo) StartTime() time.Time {
+	return common.UnixTime(r.StartTimestamp)
+}
+
+// QueuedTime returns the time when the call was queued, see QueuedTimestamp
+func (r *TelCdrInfo) QueuedTime() time.Time {
+	return common.UnixTime(r.QueuedTimestamp)
+}
+
+// RingTime returns the time when the call started ringing, see RingTimestamp
+func (r *TelCdrInfo) RingTime() time.Time {
+	return common.UnixTime(r.RingTimestamp)
+}
+
+// AcceptTime returns the time when the call was accepted, see AcceptTimestamp
+func (r *TelCdrInfo) AcceptTime() time.Time {
+	return common.UnixTime(r.AcceptTimestamp)
+}
+
+// EndedTime returns the time when the call ended, see EndedTimestamp
+func (r *TelCdrInfo) EndedTime() time.Time {
+	return common.UnixTime(r.EndedTimestamp)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2017-2018 THL A29 Limited, a Tencent company. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20200210
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+)
+
+func TestTelCdrInfoTimes(t *testing.T) {
+	info := &TelCdrInfo{
+		Time:            common.Int64Ptr(1600000000),
+		StartTimestamp:  common.Int64Ptr(1600000001),
+		QueuedTimestamp: common.Int64Ptr(1600000002),
+		RingTimestamp:   common.Int64Ptr(1600000003),
+		AcceptTimestamp: common.Int64Ptr(1600000004),
+		EndedTimestamp:  common.Int64Ptr(1600000005),
+	}
+	tests := []struct {
+		name     string
+		accessor func(*TelCdrInfo) time.Time
+		expected int64
+	}{
+		{"CallTime", (*TelCdrInfo).CallTime, 1600000000},
+		{"StartTime", (*TelCdrInfo).StartTime, 1600000001},
+		{"QueuedTime", (*TelCdrInfo).QueuedTime, 1600000002},
+		{"RingTime", (*TelCdrInfo).RingTime, 1600000003},
+		{"AcceptTime", (*TelCdrInfo).AcceptTime, 1600000004},
+		{"EndedTime", (*TelCdrInfo).EndedTime, 1600000005},
+	}
+	for _, test := range tests {
+		if actual := test.accessor(info); !actual.Equal(time.Unix(test.expected, 0)) {
+			t.Fatalf("%s: expected %d, got %v", test.name, test.expected, actual)
+		}
+		// the absent timestamp is the zero time rather than 1970-01-01
+		if actual := test.accessor(&TelCdrInfo{}); !actual.IsZero() {
+			t.Fatalf("%s: expected zero time for nil timestamp, got %v", test.name, actual)
+		}
+	}
+}
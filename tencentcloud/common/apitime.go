@@ -0,0 +1,43 @@
+package common
+
+import (
+	"fmt"
+	"time"
+
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+const (
+	// apiTimeLayout is the layout of the time strings without time zone returned by API
+	apiTimeLayout = "2006-01-02 15:04:05"
+)
+
+// apiTimeZone is the time zone of the time strings without time zone returned by API, i.e. UTC+8
+var apiTimeZone = time.FixedZone("UTC+8", 8*60*60)
+
+// ParseAPITime parses the time strings returned by API, the formats supported are
+// RFC3339, e.g. 2006-01-02T15:04:05Z, and 2006-01-02 15:04:05 which is in UTC+8.
+// An empty string results in the zero time without error.
+func ParseAPITime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(apiTimeLayout, s, apiTimeZone)
+	if err != nil {
+		msg := fmt.Sprintf("Fail to parse time %s, because: %s", s, err)
+		return time.Time{}, tcerr.NewTencentCloudSDKError("ClientError.ParseTimeError", msg, "")
+	}
+	return t, nil
+}
+
+// UnixTime converts the unix timestamp in seconds returned by API to time,
+// a nil timestamp results in the zero time.
+func UnixTime(timestamp *int64) time.Time {
+	if timestamp == nil {
+		return time.Time{}
+	}
+	return time.Unix(*timestamp, 0)
+}
@@ -0,0 +1,38 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAPITime(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    time.Time
+		wantErr bool
+	}{
+		{"", time.Time{}, false},
+		{"2021-07-01T08:00:00Z", time.Date(2021, 7, 1, 8, 0, 0, 0, time.UTC), false},
+		{"2021-07-01T16:00:00+08:00", time.Date(2021, 7, 1, 8, 0, 0, 0, time.UTC), false},
+		{"2021-07-01 16:00:00", time.Date(2021, 7, 1, 8, 0, 0, 0, time.UTC), false},
+		{"2021/07/01", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAPITime(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseAPITime(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+		}
+		if !got.Equal(tt.want) {
+			t.Fatalf("ParseAPITime(%q) got = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestUnixTime(t *testing.T) {
+	if !UnixTime(nil).IsZero() {
+		t.Fatalf("nil timestamp should result in zero time")
+	}
+	if got := UnixTime(Int64Ptr(1625126400)); !got.Equal(time.Date(2021, 7, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected time %v", got)
+	}
+}
@@ -0,0 +1,21 @@
+package common
+
+// AssumeRoleProvider assumes the role roleArn with a base credential, which can be any credential,
+// e.g. a temporary credential of the cvm role, so that the resources of another account are accessed
+// by the temporary credential of the role. The returned credential is refreshed before it expires.
+type AssumeRoleProvider struct {
+	*RoleArnProvider
+}
+
+// NewAssumeRoleProvider returns an AssumeRoleProvider which calls the STS AssumeRole API signed by base,
+// durationSeconds is the validity of the temporary credential, in the range of 0~43200s
+func NewAssumeRoleProvider(base CredentialIface, roleArn, sessionName string, durationSeconds int64) *AssumeRoleProvider {
+	return &AssumeRoleProvider{
+		RoleArnProvider: &RoleArnProvider{
+			roleArn:         roleArn,
+			roleSessionName: sessionName,
+			durationSeconds: durationSeconds,
+			baseCredential:  base,
+		},
+	}
+}
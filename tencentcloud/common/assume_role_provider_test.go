@@ -0,0 +1,108 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+// stsRT is a mock STS endpoint, it answers AssumeRole with a new temporary credential on each call
+type stsRT struct {
+	calls       int32
+	lastRequest *http.Request
+	expiredTime func() int64
+	errorCode   string
+}
+
+func (s *stsRT) RoundTrip(request *http.Request) (*http.Response, error) {
+	n := atomic.AddInt32(&s.calls, 1)
+	s.lastRequest = request
+	body := fmt.Sprintf(`{"Response": {"Credentials": {"Token": "token-%d", "TmpSecretId": "tmp-id-%d", "TmpSecretKey": "tmp-key-%d"}, "ExpiredTime": %d, "RequestId": "req-%d"}}`,
+		n, n, n, s.expiredTime(), n)
+	if s.errorCode != "" {
+		body = fmt.Sprintf(`{"Response": {"Error": {"Code": "%s", "Message": "denied"}, "RequestId": "req-%d"}}`, s.errorCode, n)
+	}
+	return &http.Response{StatusCode: 200, Header: http.Header{}, Body: ioutil.NopCloser(bytes.NewBufferString(body))}, nil
+}
+
+// withSTS sends the requests of the provider, which builds its own client, to rt
+func withSTS(t *testing.T, rt *stsRT) {
+	original := http.DefaultTransport
+	http.DefaultTransport = rt
+	t.Cleanup(func() { http.DefaultTransport = original })
+}
+
+func TestAssumeRoleProviderSignedByBase(t *testing.T) {
+	rt := &stsRT{expiredTime: func() int64 { return time.Now().Unix() + 7200 }}
+	withSTS(t, rt)
+
+	base := NewTokenCredential("base-id", "base-key", "base-token")
+	provider := NewAssumeRoleProvider(base, "qcs::cam::uin/100:roleName/test", "session", 7200)
+	credential, err := provider.GetCredential()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if credential.GetSecretId() != "tmp-id-1" || credential.GetSecretKey() != "tmp-key-1" || credential.GetToken() != "token-1" {
+		t.Fatalf("unexpected credential %s %s %s", credential.GetSecretId(), credential.GetSecretKey(), credential.GetToken())
+	}
+
+	header := rt.lastRequest.Header
+	if action := header["X-TC-Action"]; len(action) != 1 || action[0] != "AssumeRole" || rt.lastRequest.URL.Host != endpoint {
+		t.Fatalf("unexpected request %s %v", rt.lastRequest.URL, action)
+	}
+	if auth := header["Authorization"]; len(auth) != 1 || !strings.Contains(auth[0], "Credential=base-id/") {
+		t.Fatalf("AssumeRole should be signed by the base credential, got %v", auth)
+	}
+	if token := header["X-TC-Token"]; len(token) != 1 || token[0] != "base-token" {
+		t.Fatalf("unexpected token %v", token)
+	}
+	body, _ := ioutil.ReadAll(rt.lastRequest.Body)
+	if !strings.Contains(string(body), `"RoleArn":"qcs::cam::uin/100:roleName/test"`) || !strings.Contains(string(body), `"RoleSessionName":"session"`) {
+		t.Fatalf("unexpected body %s", body)
+	}
+}
+
+func TestAssumeRoleProviderRefresh(t *testing.T) {
+	// the credential is still valid for 6000s, but it is refreshed once 9/10 of its duration passed
+	rt := &stsRT{expiredTime: func() int64 { return time.Now().Unix() + 6000 }}
+	withSTS(t, rt)
+
+	provider := NewAssumeRoleProvider(NewCredential("base-id", "base-key"), "qcs::cam::uin/100:roleName/test", "session", 7200)
+	credential, err := provider.GetCredential()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if id := credential.GetSecretId(); id != "tmp-id-2" || atomic.LoadInt32(&rt.calls) != 2 {
+		t.Fatalf("credential should be refreshed before it expires, got %s after %d calls", id, rt.calls)
+	}
+
+	rt.expiredTime = func() int64 { return time.Now().Unix() + 7200 }
+	credential.GetSecretId()
+	if id := credential.GetSecretId(); id != "tmp-id-3" || atomic.LoadInt32(&rt.calls) != 3 {
+		t.Fatalf("fresh credential should not be refreshed, got %s after %d calls", id, rt.calls)
+	}
+}
+
+func TestAssumeRoleProviderError(t *testing.T) {
+	rt := &stsRT{expiredTime: func() int64 { return time.Now().Unix() + 7200 }, errorCode: "AuthFailure.SecretIdNotFound"}
+	withSTS(t, rt)
+
+	provider := NewAssumeRoleProvider(NewCredential("base-id", "base-key"), "qcs::cam::uin/100:roleName/test", "session", 7200)
+	_, err := provider.GetCredential()
+	sdkErr, ok := err.(*tcerr.TencentCloudSDKError)
+	if !ok || sdkErr.GetCode() != "AuthFailure.SecretIdNotFound" || sdkErr.GetRequestId() != "req-1" {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	provider = NewAssumeRoleProvider(NewCredential("base-id", "base-key"), "qcs::cam::uin/100:roleName/test", "session", 0)
+	if _, err = provider.GetCredential(); err == nil || atomic.LoadInt32(&rt.calls) != 1 {
+		t.Fatalf("invalid duration should be rejected before calling STS, got %+v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package common
+
+// SignatureAuditHook is invoked with the action, the credential scope, e.g. 2021-01-01/ccc/tc3_request,
+// and the signed headers, e.g. content-type;host, of every request signed with TC3-HMAC-SHA256.
+// Neither the secret id, the secret key nor the signature is passed to it.
+type SignatureAuditHook func(action, credentialScope, signedHeaders string)
+
+// WithSignatureAuditHook registers hook which is invoked after the authorization of a request is built,
+// e.g. to write the audit logs of the signing metadata. It is invoked once per signing, the retries
+// of a call reuse the signature, so they are not audited again. It is not invoked for HmacSHA1
+// and HmacSHA256, whose signatures have no credential scope. Pass nil to remove it.
+func (c *Client) WithSignatureAuditHook(hook SignatureAuditHook) *Client {
+	c.signatureAuditHook = hook
+	return c
+}
@@ -0,0 +1,77 @@
+package common
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+const (
+	// CircuitDisabled means no breaker is working, neither the adaptive retry mode nor WithRetryBudget is enabled
+	CircuitDisabled = "Disabled"
+	// CircuitClosed means retries are allowed by the retry budget
+	CircuitClosed = "Closed"
+	// CircuitOpen means the retry budget is exhausted, failures are returned without retrying
+	// until enough successful requests refill the budget
+	CircuitOpen = "Open"
+)
+
+// BreakerState is a snapshot of the health of the client, for dashboards and health checks
+type BreakerState struct {
+	// Circuit is one of CircuitDisabled, CircuitClosed and CircuitOpen,
+	// the circuit is backed by the retry budget of the adaptive retry mode or WithRetryBudget.
+	Circuit string
+	// Successes is the number of calls succeeded since the client was created
+	Successes uint64
+	// Failures is the number of calls failed because of network failures, rate limiting
+	// or internal errors of the server, errors such as invalid parameters are not counted
+	Failures uint64
+	// ConsecutiveFailures is the number of failures since the last success
+	ConsecutiveFailures uint64
+	// RetryBudgetLevel is the retry credits available, it is 0 when the circuit is disabled
+	RetryBudgetLevel float64
+	// RetryBudgetExhausted is the number of retries rejected because the circuit is open
+	RetryBudgetExhausted uint64
+}
+
+// healthCounters are updated with atomic operations, so reading them takes no lock
+type healthCounters struct {
+	successes           uint64
+	failures            uint64
+	consecutiveFailures uint64
+}
+
+func (h *healthCounters) record(err error) {
+	if err == nil {
+		atomic.AddUint64(&h.successes, 1)
+		atomic.StoreUint64(&h.consecutiveFailures, 0)
+		return
+	}
+	if sdkErr, ok := err.(*errors.TencentCloudSDKError); ok {
+		if sdkErr.Code != "ClientError.NetworkError" && sdkErr.Code != codeLimitExceeded && !strings.HasPrefix(sdkErr.Code, "InternalError") {
+			return
+		}
+	}
+	atomic.AddUint64(&h.failures, 1)
+	atomic.AddUint64(&h.consecutiveFailures, 1)
+}
+
+// BreakerState returns the current health state of the client, it is safe for concurrent use
+func (c *Client) BreakerState() BreakerState {
+	state := BreakerState{
+		Circuit:             CircuitDisabled,
+		Successes:           atomic.LoadUint64(&c.health.successes),
+		Failures:            atomic.LoadUint64(&c.health.failures),
+		ConsecutiveFailures: atomic.LoadUint64(&c.health.consecutiveFailures),
+	}
+	if budget := c.retryBudget; budget != nil {
+		state.RetryBudgetLevel = budget.level()
+		state.RetryBudgetExhausted = budget.exhaustedCount()
+		state.Circuit = CircuitClosed
+		if budget.open() {
+			state.Circuit = CircuitOpen
+		}
+	}
+	return state
+}
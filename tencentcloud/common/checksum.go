@@ -0,0 +1,99 @@
+package common
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	headerContentMD5    = "Content-MD5"
+	headerContentSHA256 = "X-TC-Content-SHA256"
+)
+
+// ErrChecksumMismatch means the response body does not match the checksum header returned by the server,
+// the body is most likely corrupted in transit
+type ErrChecksumMismatch struct {
+	// Header is the name of the checksum header, Content-MD5 or X-TC-Content-SHA256
+	Header string
+	// Expected is the checksum in the header
+	Expected string
+	// Actual is the checksum of the received body
+	Actual string
+}
+
+func (e *ErrChecksumMismatch) Error() string {
+	return fmt.Sprintf("checksum mismatch: %s expected %s, actual %s", e.Header, e.Expected, e.Actual)
+}
+
+// checksum is the expected digest of the body specified by a header
+type checksum struct {
+	header   string
+	expected string
+	hash     hash.Hash
+}
+
+// newChecksum returns nil if header carries no checksum, the SHA256 header is preferred
+func newChecksum(header http.Header) *checksum {
+	if v := header.Get(headerContentSHA256); v != "" && v != "UNSIGNED-PAYLOAD" {
+		return &checksum{header: headerContentSHA256, expected: v, hash: sha256.New()}
+	}
+	if v := header.Get(headerContentMD5); v != "" {
+		return &checksum{header: headerContentMD5, expected: v, hash: md5.New()}
+	}
+	return nil
+}
+
+// verify compares the digest written so far with the expected one,
+// Content-MD5 is base64 encoded as RFC 1864 requires, hex is accepted as well
+func (c *checksum) verify() error {
+	sum := c.hash.Sum(nil)
+	if strings.EqualFold(c.expected, hex.EncodeToString(sum)) || c.expected == base64.StdEncoding.EncodeToString(sum) {
+		return nil
+	}
+	return &ErrChecksumMismatch{Header: c.header, Expected: c.expected, Actual: hex.EncodeToString(sum)}
+}
+
+// verifyChecksum verifies body against the checksum header, it returns nil if there is no checksum header
+func verifyChecksum(header http.Header, body []byte) error {
+	c := newChecksum(header)
+	if c == nil {
+		return nil
+	}
+	c.hash.Write(body)
+	return c.verify()
+}
+
+// NewChecksumReader wraps the body of a streaming response, it verifies the body against
+// the Content-MD5 or X-TC-Content-SHA256 header when the body is read to the end,
+// and returns *ErrChecksumMismatch instead of io.EOF on mismatch.
+// The body is returned as is if there is no checksum header.
+func NewChecksumReader(body io.ReadCloser, header http.Header) io.ReadCloser {
+	c := newChecksum(header)
+	if c == nil {
+		return body
+	}
+	return &checksumReader{ReadCloser: body, checksum: c}
+}
+
+type checksumReader struct {
+	io.ReadCloser
+	checksum *checksum
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.checksum.hash.Write(p[:n])
+	if err == io.EOF {
+		if verr := r.checksum.verify(); verr != nil {
+			return n, verr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,65 @@
+package common
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func newHeader(key, value string) http.Header {
+	header := http.Header{}
+	header.Set(key, value)
+	return header
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	body := []byte(`{"Response": {"RequestId": "req-1"}}`)
+	md5sum := md5.Sum(body)
+	sha256sum := sha256.Sum256(body)
+
+	valid := []http.Header{
+		{},
+		newHeader(headerContentMD5, base64.StdEncoding.EncodeToString(md5sum[:])),
+		newHeader(headerContentMD5, hex.EncodeToString(md5sum[:])),
+		newHeader(headerContentSHA256, hex.EncodeToString(sha256sum[:])),
+	}
+	for _, header := range valid {
+		if err := verifyChecksum(header, body); err != nil {
+			t.Fatalf("unexpected error for %v: %+v", header, err)
+		}
+	}
+
+	invalid := []http.Header{
+		newHeader(headerContentMD5, base64.StdEncoding.EncodeToString(md5sum[:])),
+		newHeader(headerContentSHA256, hex.EncodeToString(sha256sum[:])),
+	}
+	for _, header := range invalid {
+		var mismatch *ErrChecksumMismatch
+		if err := verifyChecksum(header, body[1:]); !errors.As(err, &mismatch) {
+			t.Fatalf("expected ErrChecksumMismatch for %v, got %+v", header, err)
+		}
+	}
+}
+
+func TestChecksumReader(t *testing.T) {
+	body := []byte("recording content")
+	md5sum := md5.Sum(body)
+	header := newHeader(headerContentMD5, base64.StdEncoding.EncodeToString(md5sum[:]))
+
+	reader := NewChecksumReader(ioutil.NopCloser(bytes.NewReader(body)), header)
+	if b, err := ioutil.ReadAll(reader); err != nil || !bytes.Equal(b, body) {
+		t.Fatalf("unexpected result %s, %+v", b, err)
+	}
+
+	reader = NewChecksumReader(ioutil.NopCloser(bytes.NewReader(body[1:])), header)
+	var mismatch *ErrChecksumMismatch
+	if _, err := ioutil.ReadAll(reader); !errors.As(err, &mismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %+v", err)
+	}
+}
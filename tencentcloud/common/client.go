@@ -1,32 +1,98 @@
 package common
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"net/http/httputil"
-	"strconv"
 	"strings"
 	"time"
 
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
 	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
+	"golang.org/x/time/rate"
 )
 
 type Client struct {
 	region          string
 	httpClient      *http.Client
+	customTransport bool
 	httpProfile     *profile.HttpProfile
 	profile         *profile.ClientProfile
 	credential      CredentialIface
+	providers       []string
 	signMethod      string
 	unsignedPayload bool
 	debug           bool
+	retryBudget     *retryBudget
+	rateLimiter     *rate.Limiter
+	requestClient   string
+	cryptoProvider  CryptoProvider
+
+	retryExhaustedHook func(event RetryExhaustedEvent)
+	readCache          *readCache
+	responseCache      *responseCache
+	singleFlight       *callGroup
+	timestampFunc      TimestampFunc
+	dryRun             *dryRun
+	errorMapper        ErrorMapper
+	health             *healthCounters
+	clockSkew          *clockSkew
+	endpointCache      *endpointCache
+	endpointResolver   EndpointResolver
+	defaultHeaders     map[string]string
+	interceptor        ResponseInterceptor
+	preferredDomain    int32
+	retryHook          RetryHook
+	closed             int32
+	inflight           chan struct{}
+	signer             Signer
+	timeSource         func() time.Time
+	nonceFunc          func() int
+	debugWriter        io.Writer
+	debugNoRedaction   bool
+
+	clientTokenGenerator func() string
+	// retryBudgetRatio is set by WithRetryBudget, it replaces the budget of the profile if positive
+	retryBudgetRatio   float64
+	signatureAuditHook SignatureAuditHook
+	retryDecider       RetryDecider
+	timingCapture      bool
+	// excludedQueryParams is set by WithExcludedQueryParams, nil means DefaultExcludedQueryParams
+	excludedQueryParams []string
+	// signingKeys is nil if the signing keys are not cached
+	signingKeys *signingKeyCache
+	// rawBodyLimit is set by WithRawBody, the raw bodies are not kept if it is 0
+	rawBodyLimit int64
+}
+
+func (c *Client) Send(request tchttp.Request, response tchttp.Response) error {
+	defer c.dropRawBody(response)
+	return c.sendRequest(request, response)
 }
 
-func (c *Client) Send(request tchttp.Request, response tchttp.Response) (err error) {
+// sendRequest sends request as Send does, but the raw body of response is always kept
+func (c *Client) sendRequest(request tchttp.Request, response tchttp.Response) (err error) {
+	defer func() {
+		err = c.mapError(err)
+	}()
+
+	if err = c.checkClosed(); err != nil {
+		return err
+	}
+
+	if err = c.checkRegion(request); err != nil {
+		return err
+	}
+
+	if err = ValidateRequest(request); err != nil {
+		return tcerr.NewTencentCloudSDKErrorWithCause("ClientError.InvalidParameter", err.Error(), "", err)
+	}
+
 	if request.GetScheme() == "" {
 		request.SetScheme(c.httpProfile.Scheme)
 	}
@@ -35,12 +101,10 @@ func (c *Client) Send(request tchttp.Request, response tchttp.Response) (err err
 		request.SetRootDomain(c.httpProfile.RootDomain)
 	}
 
+	// the backup domains only replace the domain resolved by the client
+	failover := request.GetDomain() == "" && c.httpProfile.Endpoint == "" && len(c.httpProfile.BackupDomains) > 0
 	if request.GetDomain() == "" {
-		domain := c.httpProfile.Endpoint
-		if domain == "" {
-			domain = request.GetServiceDomain(request.GetService())
-		}
-		request.SetDomain(domain)
+		request.SetDomain(c.resolveDomain(request))
 	}
 
 	if request.GetHttpMethod() == "" {
@@ -48,38 +112,145 @@ func (c *Client) Send(request tchttp.Request, response tchttp.Response) (err err
 	}
 
 	tchttp.CompleteCommonParams(request, c.GetRegion())
+	c.completeNonce(request.GetParams())
+	if c.requestClient != "" {
+		request.GetParams()["RequestClient"] += " " + c.requestClient
+	}
+	if err = c.completeTimestamp(request.GetParams()); err != nil {
+		return err
+	}
+
+	// reflect to inject client token if field exists and retry feature is enabled,
+	// the token specified by request.SetClientToken is always used if field exists,
+	// the token a clone copied from its template is never reused
+	resetClonedClientToken(request)
+	if token := request.GetClientToken(); token != "" {
+		injectClientToken(request, func() string { return token })
+	} else if c.profile.NetworkFailureMaxRetries > 0 || c.profile.RateLimitExceededMaxRetries > 0 {
+		c.injectClientTokenFor(request)
+	}
+
+	if err = c.checkCredentialExpired(); err != nil {
+		return err
+	}
 
-	// reflect to inject client client if field exists and retry feature is enabled
-	if c.profile.NetworkFailureMaxRetries > 0 || c.profile.RateLimitExceededMaxRetries > 0 {
-		safeInjectClientToken(request)
+	release, err := c.acquireSlot(request.GetContext())
+	if err != nil {
+		return err
 	}
+	defer release()
 
+	send := c.send
+	if failover {
+		send = c.sendWithFailover
+	}
+	cacheable := !bypassCache(request)
+	if group := c.singleFlight; group != nil && cacheable && IsReadAction(request.GetAction()) {
+		send = c.sendWithSingleFlight(group, send)
+	}
+	if cacheable && c.readCache != nil && c.readCache.actions[request.GetAction()] {
+		err = c.sendWithReadCache(request, response)
+	} else if cacheable && c.responseCache != nil && c.responseCache.cacheable(request.GetAction()) {
+		err = c.sendWithResponseCache(request, response, send)
+	} else {
+		err = send(request, response)
+	}
+	if err == nil {
+		c.interceptResponse(request.GetAction(), response)
+	}
+	return err
+}
+
+func (c *Client) send(request tchttp.Request, response tchttp.Response) (err error) {
+	offset := c.clockOffset()
+	err = c.sendWithSignature(request, response)
+	// retry once with the corrected timestamp if the local clock is skewed
+	if isSignatureExpire(err) && c.clockOffset() != offset {
+		if err = c.completeTimestamp(request.GetParams()); err != nil {
+			return err
+		}
+		err = c.sendWithSignature(request, response)
+	}
+	return err
+}
+
+func (c *Client) sendWithSignature(request tchttp.Request, response tchttp.Response) (err error) {
+	trace := c.newTimingTrace()
 	if c.signMethod == "HmacSHA1" || c.signMethod == "HmacSHA256" {
-		return c.sendWithSignatureV1(request, response)
+		if c.profile.DisableSignatureV1 {
+			msg := fmt.Sprintf("Sign method %s is disabled by DisableSignatureV1, please use TC3-HMAC-SHA256", c.signMethod)
+			return tcerr.NewTencentCloudSDKError("ClientError.SignatureV1Disabled", msg, "")
+		}
+		return c.sendWithSignatureV1(request, response, trace)
 	} else {
-		return c.sendWithSignatureV3(request, response)
+		return c.sendWithSignatureV3(request, response, trace)
+	}
+}
+
+// language returns the language of request, which overrides the one of the client profile
+func (c *Client) language(request tchttp.Request) string {
+	if language, ok := request.GetLanguage(); ok {
+		return language
 	}
+	return c.profile.Language
 }
 
-func (c *Client) sendWithSignatureV1(request tchttp.Request, response tchttp.Response) (err error) {
+// checkRequestBodySize returns an error instead of sending the body larger than HttpProfile.MaxRequestBodyBytes
+func (c *Client) checkRequestBodySize(request tchttp.Request, size int64) error {
+	if c.debug {
+		log.Printf("[DEBUG] %s request payload size = %d bytes", request.GetAction(), size)
+	}
+	if limit := c.httpProfile.MaxRequestBodyBytes; limit > 0 && size > limit {
+		msg := fmt.Sprintf("The request body of %s is %d bytes, which exceeds the limit of %d bytes", request.GetAction(), size, limit)
+		return tcerr.NewTencentCloudSDKError("ClientError.RequestBodyTooLarge", msg, "")
+	}
+	return nil
+}
+
+// isUnsignedPayload returns whether the payload of request is signed, the choice of request overrides the client profile
+func (c *Client) isUnsignedPayload(request tchttp.Request) bool {
+	if unsigned, ok := request.GetUnsignedPayload(); ok {
+		return unsigned
+	}
+	return c.unsignedPayload
+}
+
+func (c *Client) sendWithSignatureV1(request tchttp.Request, response tchttp.Response, trace *timingTrace) (err error) {
+	if cr, ok := request.(*tchttp.CommonRequest); ok && cr.IsMultipart() {
+		return tcerr.NewTencentCloudSDKError("ClientError.InvalidParameter", "multipart body requires the TC3-HMAC-SHA256 sign method", "")
+	}
+	if c.signer != nil && c.signMethod != SHA256 {
+		msg := fmt.Sprintf("Sign method %s is not supported by the Signer, please use HmacSHA256 or TC3-HMAC-SHA256", c.signMethod)
+		return tcerr.NewTencentCloudSDKError("ClientError.InvalidParameter", msg, "")
+	}
 	// TODO: not an elegant way, it should be done in common params, but finally it need to refactor
-	request.GetParams()["Language"] = c.profile.Language
+	if language := c.language(request); language != "" {
+		request.GetParams()["Language"] = language
+	} else {
+		delete(request.GetParams(), "Language")
+	}
 	err = tchttp.ConstructParams(request)
 	if err != nil {
 		return err
 	}
-	err = signRequest(request, c.credential, c.signMethod)
+	err = signRequest(request, c.credential, c.signMethod, c.v1HmacSHA256)
 	if err != nil {
 		return err
 	}
-	httpRequest, err := http.NewRequest(request.GetHttpMethod(), request.GetUrl(), request.GetBodyReader())
+	httpRequest, err := http.NewRequestWithContext(request.GetContext(), request.GetHttpMethod(), request.GetUrl(), request.GetBodyReader())
 	if err != nil {
 		return err
 	}
+	if err = c.checkRequestBodySize(request, httpRequest.ContentLength); err != nil {
+		return err
+	}
 	if request.GetHttpMethod() == "POST" {
 		httpRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	}
-	httpResponse, err := c.sendWithRateLimitRetry(httpRequest, isRetryable(request))
+	for k, v := range c.customHeaders(request) {
+		httpRequest.Header.Set(k, v)
+	}
+	httpResponse, err := c.sendWithRateLimitRetry(httpRequest, request, response, trace)
 	if err != nil {
 		return err
 	}
@@ -87,14 +258,16 @@ func (c *Client) sendWithSignatureV1(request tchttp.Request, response tchttp.Res
 	return err
 }
 
-func (c *Client) sendWithSignatureV3(request tchttp.Request, response tchttp.Response) (err error) {
+func (c *Client) sendWithSignatureV3(request tchttp.Request, response tchttp.Response, trace *timingTrace) (err error) {
 	headers := map[string]string{
 		"Host":               request.GetDomain(),
 		"X-TC-Action":        request.GetAction(),
 		"X-TC-Version":       request.GetVersion(),
 		"X-TC-Timestamp":     request.GetParams()["Timestamp"],
 		"X-TC-RequestClient": request.GetParams()["RequestClient"],
-		"X-TC-Language":      c.profile.Language,
+	}
+	if language := c.language(request); language != "" {
+		headers["X-TC-Language"] = language
 	}
 	if c.region != "" {
 		headers["X-TC-Region"] = c.region
@@ -108,18 +281,23 @@ func (c *Client) sendWithSignatureV3(request tchttp.Request, response tchttp.Res
 		headers["Content-Type"] = "application/json"
 	}
 	isOctetStream := false
+	isMultipart := false
 	cr := &tchttp.CommonRequest{}
 	ok := false
 	if cr, ok = request.(*tchttp.CommonRequest); ok {
 		if cr.IsOctetStream() {
 			isOctetStream = true
 			// custom headers must contain Content-Type : application/octet-stream
-			// todo:the custom header may overwrite headers
-			for k, v := range cr.GetHeader() {
-				headers[k] = v
-			}
+			headers["Content-Type"] = cr.GetHeader()["Content-Type"]
+		} else if cr.IsMultipart() {
+			isMultipart = true
+			// the boundary in Content-Type is signed, it must be the one of the body
+			headers["Content-Type"] = cr.GetHeader()["Content-Type"]
 		}
 	}
+	for k, v := range c.customHeaders(request) {
+		headers[k] = v
+	}
 	// start signature v3 process
 
 	// build canonical request string
@@ -135,21 +313,36 @@ func (c *Client) sendWithSignatureV3(request tchttp.Request, response tchttp.Res
 		for key, value := range request.GetParams() {
 			params[key] = value
 		}
-		delete(params, "Action")
-		delete(params, "Version")
-		delete(params, "Nonce")
-		delete(params, "Region")
-		delete(params, "RequestClient")
-		delete(params, "Timestamp")
-		canonicalQueryString = tchttp.GetUrlQueriesEncoded(params)
-	}
-	canonicalHeaders := fmt.Sprintf("content-type:%s\nhost:%s\n", headers["Content-Type"], headers["Host"])
-	signedHeaders := "content-type;host"
+		// the action parameters of a common request are not struct fields flattened by ConstructParams
+		if ok && !isOctetStream && !isMultipart {
+			flatParams, err := cr.GetFlatParams()
+			if err != nil {
+				return err
+			}
+			for key, value := range flatParams {
+				params[key] = value
+			}
+		}
+		for _, name := range c.excludedQueryParamsOrDefault() {
+			delete(params, name)
+		}
+		canonicalQueryString = tchttp.GetCanonicalQueryString(params)
+	} else if ok && !isOctetStream && !isMultipart && cr.GetJsonBody() == nil {
+		params, err := cr.GetQueryParams()
+		if err != nil {
+			return err
+		}
+		canonicalQueryString = tchttp.GetCanonicalQueryString(params)
+	}
 	requestPayload := ""
 	if httpRequestMethod == "POST" {
 		if isOctetStream {
 			// todo Conversion comparison between string and []byte affects performance much
 			requestPayload = string(cr.GetOctetStreamBody())
+		} else if isMultipart {
+			requestPayload = string(cr.GetMultipartBody())
+		} else if ok && cr.GetJsonBody() != nil {
+			requestPayload = string(cr.GetJsonBody())
 		} else {
 			b, err := json.Marshal(request)
 			if err != nil {
@@ -158,12 +351,23 @@ func (c *Client) sendWithSignatureV3(request tchttp.Request, response tchttp.Res
 			requestPayload = string(b)
 		}
 	}
+	if err = c.checkRequestBodySize(request, int64(len(requestPayload))); err != nil {
+		return err
+	}
 	hashedRequestPayload := ""
-	if c.unsignedPayload {
-		hashedRequestPayload = sha256hex("UNSIGNED-PAYLOAD")
+	if c.isUnsignedPayload(request) {
+		hashedRequestPayload = c.sha256hex("UNSIGNED-PAYLOAD")
 		headers["X-TC-Content-SHA256"] = "UNSIGNED-PAYLOAD"
 	} else {
-		hashedRequestPayload = sha256hex(requestPayload)
+		hashedRequestPayload = c.sha256hex(requestPayload)
+	}
+	var extraSignedHeaders []string
+	if ok {
+		extraSignedHeaders = cr.GetSignedHeaders()
+	}
+	canonicalHeaders, signedHeaders, err := buildCanonicalHeaders(headers, extraSignedHeaders)
+	if err != nil {
+		return err
 	}
 	canonicalRequest := fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s",
 		httpRequestMethod,
@@ -177,12 +381,15 @@ func (c *Client) sendWithSignatureV3(request tchttp.Request, response tchttp.Res
 	// build string to sign
 	algorithm := "TC3-HMAC-SHA256"
 	requestTimestamp := headers["X-TC-Timestamp"]
-	timestamp, _ := strconv.ParseInt(requestTimestamp, 10, 64)
-	t := time.Unix(timestamp, 0).UTC()
+	signTime, err := parseTimestamp(requestTimestamp, true)
+	if err != nil {
+		return err
+	}
+	t := signTime.UTC()
 	// must be the format 2006-01-02, ref to package time for more info
 	date := t.Format("2006-01-02")
 	credentialScope := fmt.Sprintf("%s/%s/tc3_request", date, request.GetService())
-	hashedCanonicalRequest := sha256hex(canonicalRequest)
+	hashedCanonicalRequest := c.sha256hex(canonicalRequest)
 	string2sign := fmt.Sprintf("%s\n%s\n%s\n%s",
 		algorithm,
 		requestTimestamp,
@@ -191,10 +398,8 @@ func (c *Client) sendWithSignatureV3(request tchttp.Request, response tchttp.Res
 	//log.Println("string2sign", string2sign)
 
 	// sign string
-	secretDate := hmacsha256(date, "TC3"+c.credential.GetSecretKey())
-	secretService := hmacsha256(request.GetService(), secretDate)
-	secretKey := hmacsha256("tc3_request", secretService)
-	signature := hex.EncodeToString([]byte(hmacsha256(string2sign, secretKey)))
+	secretKey := c.signingKey(date, request.GetService(), c.credential.GetSecretId(), c.credential.GetSecretKey())
+	signature := hex.EncodeToString([]byte(c.hmacsha256(string2sign, secretKey)))
 	//log.Println("signature", signature)
 
 	// build authorization
@@ -205,20 +410,23 @@ func (c *Client) sendWithSignatureV3(request tchttp.Request, response tchttp.Res
 		signedHeaders,
 		signature)
 	//log.Println("authorization", authorization)
+	if c.signatureAuditHook != nil {
+		c.signatureAuditHook(request.GetAction(), credentialScope, signedHeaders)
+	}
 
 	headers["Authorization"] = authorization
 	url := request.GetScheme() + "://" + request.GetDomain() + request.GetPath()
 	if canonicalQueryString != "" {
 		url = url + "?" + canonicalQueryString
 	}
-	httpRequest, err := http.NewRequest(httpRequestMethod, url, strings.NewReader(requestPayload))
+	httpRequest, err := http.NewRequestWithContext(request.GetContext(), httpRequestMethod, url, strings.NewReader(requestPayload))
 	if err != nil {
 		return err
 	}
 	for k, v := range headers {
 		httpRequest.Header[k] = []string{v}
 	}
-	httpResponse, err := c.sendWithRateLimitRetry(httpRequest, isRetryable(request))
+	httpResponse, err := c.sendWithRateLimitRetry(httpRequest, request, response, trace)
 	if err != nil {
 		return err
 	}
@@ -228,28 +436,69 @@ func (c *Client) sendWithSignatureV3(request tchttp.Request, response tchttp.Res
 
 // send http request
 func (c *Client) sendHttp(request *http.Request) (response *http.Response, err error) {
+	// the body is consumed by the previous attempt, every retry sends a fresh copy of it
+	if request.GetBody != nil {
+		if request.Body, err = request.GetBody(); err != nil {
+			return nil, err
+		}
+	}
 	if c.debug {
-		outbytes, err := httputil.DumpRequest(request, true)
-		if err != nil {
-			log.Printf("[ERROR] dump request failed because %s", err)
+		if err = c.dumpRequest(request, "request"); err != nil {
 			return nil, err
 		}
-		log.Printf("[DEBUG] http request = %s", outbytes)
 	}
 
-	response, err = c.httpClient.Do(request)
+	response, err = c.doHttp(request)
+	if err == nil && c.debug {
+		c.dumpResponse(response)
+	}
 	return response, err
 }
 
+func (c *Client) doHttp(request *http.Request) (*http.Response, error) {
+	// the timeout of the profile applies to each attempt unless the context has a deadline,
+	// which is shared by all the attempts of the call
+	if _, ok := request.Context().Deadline(); ok || c.httpProfile.ReqTimeout <= 0 {
+		return c.httpClient.Do(request)
+	}
+	ctx, cancel := context.WithTimeout(request.Context(), time.Duration(c.httpProfile.ReqTimeout)*time.Second)
+	response, err := c.httpClient.Do(request.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// the context must live until the body is read
+	response.Body = &cancelOnClose{ReadCloser: response.Body, cancel: cancel}
+	return response, nil
+}
+
+// cancelOnClose cancels the context of the request when the response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
 func (c *Client) GetRegion() string {
 	return c.region
 }
 
+// Init initializes the client, the environment variable TENCENTCLOUD_REGION is used if region is empty
 func (c *Client) Init(region string) *Client {
 	c.httpClient = &http.Client{}
-	c.region = region
+	c.region = resolveRegion(region)
 	c.signMethod = "TC3-HMAC-SHA256"
 	c.debug = false
+	c.cryptoProvider = DefaultCryptoProvider()
+	c.health = &healthCounters{}
+	c.clockSkew = &clockSkew{}
+	c.endpointCache = &endpointCache{domains: make(map[endpointKey]string)}
+	c.signingKeys = newSigningKeyCache()
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 	return c
 }
@@ -261,6 +510,7 @@ func (c *Client) WithSecretId(secretId, secretKey string) *Client {
 
 func (c *Client) WithCredential(cred CredentialIface) *Client {
 	c.credential = cred
+	c.providers = nil
 	return c
 }
 
@@ -270,7 +520,15 @@ func (c *Client) WithProfile(clientProfile *profile.ClientProfile) *Client {
 	c.unsignedPayload = clientProfile.UnsignedPayload
 	c.httpProfile = clientProfile.HttpProfile
 	c.debug = clientProfile.Debug
-	c.httpClient.Timeout = time.Duration(c.httpProfile.ReqTimeout) * time.Second
+	c.resetRetryBudget()
+	// the timeout is applied by the context of each request, so that it can be overridden per call
+	c.httpClient.Timeout = 0
+	if !c.customTransport {
+		c.httpClient.Transport = newTransport(c.httpProfile)
+	}
+	if c.debug {
+		log.Printf("[DEBUG] client config = %s", c.DumpConfig())
+	}
 	return c
 }
 
@@ -279,8 +537,11 @@ func (c *Client) WithSignatureMethod(method string) *Client {
 	return c
 }
 
+// WithHttpTransport replaces the transport built from the HttpProfile,
+// so the transport settings of the HttpProfile, e.g. ProxyURL, take no effect after it is called.
 func (c *Client) WithHttpTransport(transport http.RoundTripper) *Client {
 	c.httpClient.Transport = transport
+	c.customTransport = true
 	return c
 }
 
@@ -289,13 +550,43 @@ func (c *Client) WithDebug(flag bool) *Client {
 	return c
 }
 
+// WithCryptoProvider replaces the hash primitives used by signature v3 and HmacSHA256 of signature v1,
+// e.g. with a FIPS validated implementation, nil restores the default one.
+func (c *Client) WithCryptoProvider(provider CryptoProvider) *Client {
+	if provider == nil {
+		provider = DefaultCryptoProvider()
+	}
+	c.cryptoProvider = provider
+	c.resetSigningKeys()
+	return c
+}
+
+// WithRequestClient appends the identifier of your application to the RequestClient
+// reported to the server, e.g. "SDK_GO_1.0.224 MyApp/2.3", which helps to correlate
+// issues with your application when you contact the technical support.
+func (c *Client) WithRequestClient(suffix string) *Client {
+	c.requestClient = suffix
+	return c
+}
+
+// WithRateLimiter throttles the http requests sent by the client, including retries,
+// to at most limit requests per second with bursts of at most burst requests.
+// The limiter is shared by all goroutines using the client,
+// waiting for the limiter is canceled along with the context of the request.
+func (c *Client) WithRateLimiter(limit rate.Limit, burst int) *Client {
+	c.rateLimiter = rate.NewLimiter(limit, burst)
+	return c
+}
+
 // WithProvider use specify provider to get a credential and use it to build a client
 func (c *Client) WithProvider(provider Provider) (*Client, error) {
 	cred, err := provider.GetCredential()
 	if err != nil {
 		return nil, err
 	}
-	return c.WithCredential(cred), nil
+	c.WithCredential(cred)
+	c.providers = providerNames(provider)
+	return c, nil
 }
 
 func NewClientWithSecretId(secretId, secretKey, region string) (client *Client, err error) {
@@ -305,7 +596,9 @@ func NewClientWithSecretId(secretId, secretKey, region string) (client *Client,
 }
 
 // NewClientWithProviders build client with your custom providers;
-// If you don't specify the providers, it will use the DefaultProviderChain to find credential
+// If you don't specify the providers, it will use the DefaultProviderChain to find credential.
+// If region is empty, the environment variable TENCENTCLOUD_REGION is used. The client without a region
+// is still built for the global services, the requests of the region scoped services are rejected when sent.
 func NewClientWithProviders(region string, providers ...Provider) (client *Client, err error) {
 	client = (&Client{}).Init(region)
 	var pc Provider
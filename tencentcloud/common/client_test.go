@@ -2,14 +2,32 @@ package common_test
 
 import (
 	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io/ioutil"
+	"log"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
 	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/regions"
+	"golang.org/x/time/rate"
 )
 
 type requestWithClientToken struct {
@@ -42,9 +60,14 @@ type mockRT struct {
 
 	NetworkTries   int
 	RateLimitTries int
+
+	Requests    int
+	LastRequest *http.Request
 }
 
 func (s *mockRT) RoundTrip(request *http.Request) (*http.Response, error) {
+	s.Requests++
+	s.LastRequest = request
 	if s.NetworkTries < s.NetworkFailures {
 		s.NetworkTries++
 		return nil, retryErr{}
@@ -171,6 +194,162 @@ func TestBothFailuresOccurredButSucceedAfterRetry(t *testing.T) {
 	})
 }
 
+func TestNetworkFailureIsTypedError(t *testing.T) {
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	client.WithHttpTransport(&mockRT{NetworkFailures: 1})
+
+	err := client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	sdkErr, ok := err.(*tcerr.TencentCloudSDKError)
+	if !ok || sdkErr.GetCode() != "ClientError.NetworkError" {
+		t.Fatalf("unexpected error, expected ClientError.NetworkError, got %+v", err)
+	}
+	var cause retryErr
+	if !errors.As(err, &cause) {
+		t.Fatalf("underlying network error is not accessible from %+v", err)
+	}
+}
+
+func TestRateLimiterRespectsContext(t *testing.T) {
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	client.WithHttpTransport(&mockRT{})
+	client.WithRateLimiter(rate.Limit(0.001), 1)
+
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request within burst: %+v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request := newTestRequest()
+	request.SetContext(ctx)
+	err := client.Send(request, tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.RateLimiterError" {
+		t.Fatalf("unexpected error, expected ClientError.RateLimiterError, got %+v", err)
+	}
+}
+
+func TestServiceForDomain(t *testing.T) {
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	client.WithHttpTransport(&mockRT{})
+
+	request := newTestRequest()
+	request.SetServiceForDomain("cvm-gateway")
+	if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	if request.GetDomain() != "cvm-gateway.tencentcloudapi.com" || request.GetService() != "cvm" {
+		t.Fatalf("unexpected domain %s for service %s", request.GetDomain(), request.GetService())
+	}
+}
+
+func TestRequestClient(t *testing.T) {
+	for _, signMethod := range []string{"HmacSHA256", "TC3-HMAC-SHA256"} {
+		prof := profile.NewClientProfile()
+		prof.SignMethod = signMethod
+		credential := common.NewCredential("", "")
+		client := common.NewCommonClient(credential, regions.Guangzhou, prof)
+		rt := &mockRT{}
+		client.WithHttpTransport(rt).WithRequestClient("MyApp/2.3")
+
+		request := newTestRequest()
+		if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("unexpected failed on request: %+v", err)
+		}
+		requestClient := request.GetParams()["RequestClient"]
+		if !strings.HasPrefix(requestClient, "SDK_GO_") || !strings.HasSuffix(requestClient, " MyApp/2.3") {
+			t.Fatalf("unexpected RequestClient %s", requestClient)
+		}
+		if header := rt.LastRequest.Header["X-TC-RequestClient"]; signMethod == "TC3-HMAC-SHA256" &&
+			(len(header) != 1 || header[0] != requestClient) {
+			t.Fatalf("unexpected X-TC-RequestClient header %v", header)
+		}
+	}
+}
+
+func TestSpecifiedClientTokenNotOverwritten(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.NetworkFailureMaxRetries = 1
+	prof.NetworkFailureRetryDuration = profile.ConstantDurationFunc(0)
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, prof)
+	client.WithHttpTransport(&mockRT{})
+
+	request := newTestRequest()
+	request.SetClientToken("deterministic-token")
+	if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	if request.ClientToken == nil || *request.ClientToken != "deterministic-token" {
+		t.Fatalf("unexpected client token %v", request.ClientToken)
+	}
+}
+
+type countingCryptoProvider struct {
+	common.CryptoProvider
+	Sha256Calls int
+	HmacCalls   int
+}
+
+func (p *countingCryptoProvider) Sha256(data []byte) []byte {
+	p.Sha256Calls++
+	return p.CryptoProvider.Sha256(data)
+}
+
+func (p *countingCryptoProvider) HmacSha256(key, data []byte) []byte {
+	p.HmacCalls++
+	return p.CryptoProvider.HmacSha256(key, data)
+}
+
+func TestCustomCryptoProvider(t *testing.T) {
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	provider := &countingCryptoProvider{CryptoProvider: common.DefaultCryptoProvider()}
+	client.WithHttpTransport(&mockRT{}).WithCryptoProvider(provider)
+
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	if provider.Sha256Calls != 2 || provider.HmacCalls != 4 {
+		t.Fatalf("unexpected crypto provider calls, sha256 %d, hmac %d", provider.Sha256Calls, provider.HmacCalls)
+	}
+
+	// HmacSHA256 of signature v1 is computed by the provider as well, HmacSHA1 is not
+	for method, calls := range map[string]int{"HmacSHA256": 1, "HmacSHA1": 0} {
+		provider.Sha256Calls, provider.HmacCalls = 0, 0
+		client.WithSignatureMethod(method)
+		if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("%s: unexpected failed on request: %+v", method, err)
+		}
+		if provider.Sha256Calls != 0 || provider.HmacCalls != calls {
+			t.Fatalf("%s: unexpected crypto provider calls, sha256 %d, hmac %d", method, provider.Sha256Calls, provider.HmacCalls)
+		}
+	}
+}
+
+func TestAdaptiveRetryConsumesBudget(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.RetryMode = profile.RetryModeAdaptive
+	prof.RateLimitExceededMaxRetries = 1
+	prof.RateLimitExceededRetryDuration = profile.ConstantDurationFunc(0)
+
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, prof)
+	client.WithHttpTransport(&mockRT{RateLimitFailures: 1})
+
+	err := client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	if err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+
+	level, enabled := client.RetryBudgetLevel()
+	if !enabled || level != 496 {
+		t.Fatalf("unexpected retry budget level, expected %d, got %f", 496, level)
+	}
+}
+
 func test(t *testing.T, tc testCase) {
 	credential := common.NewCredential("", "")
 	client := common.NewCommonClient(credential, regions.Guangzhou, tc.prof)
@@ -192,3 +371,2043 @@ func test(t *testing.T, tc testCase) {
 			tc.expected.NetworkTries, tc.specific.NetworkTries)
 	}
 }
+
+func TestRetryExhaustedHook(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.NetworkFailureMaxRetries = 1
+	prof.NetworkFailureRetryDuration = profile.ConstantDurationFunc(time.Millisecond)
+	prof.RateLimitExceededMaxRetries = 1
+	prof.RateLimitExceededRetryDuration = profile.ConstantDurationFunc(time.Millisecond)
+
+	var events []common.RetryExhaustedEvent
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, prof)
+	client.WithRetryExhaustedHook(func(event common.RetryExhaustedEvent) {
+		events = append(events, event)
+	})
+
+	client.WithHttpTransport(&mockRT{RateLimitFailures: 3})
+	err := client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	if err == nil {
+		t.Fatalf("expected rate limit error")
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected exactly 1 event, got %d", len(events))
+	}
+	if events[0].Attempts != 2 || events[0].Delay != time.Millisecond || events[0].Err != err {
+		t.Fatalf("unexpected event %+v", events[0])
+	}
+
+	events = nil
+	client.WithHttpTransport(&mockRT{NetworkFailures: 3})
+	err = client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	if err == nil {
+		t.Fatalf("expected network error")
+	}
+	if len(events) != 1 || events[0].Attempts != 2 || events[0].Err != err {
+		t.Fatalf("unexpected events %+v", events)
+	}
+
+	events = nil
+	client.WithHttpTransport(&mockRT{RateLimitFailures: 1})
+	if err = client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("hook should not fire on success, got %+v", events)
+	}
+}
+
+func TestRootDomain(t *testing.T) {
+	for _, signMethod := range []string{"HmacSHA256", "TC3-HMAC-SHA256"} {
+		prof := profile.NewClientProfile()
+		prof.SignMethod = signMethod
+		if err := prof.HttpProfile.WithRootDomain(profile.RootDomainShanghaiFsi); err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		credential := common.NewCredential("", "")
+		client := common.NewCommonClient(credential, regions.Guangzhou, prof)
+		rt := &mockRT{}
+		client.WithHttpTransport(rt)
+
+		if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("unexpected failed on request: %+v", err)
+		}
+		expected := "cvm." + profile.RootDomainShanghaiFsi
+		if rt.LastRequest.URL.Host != expected {
+			t.Fatalf("%s: unexpected host, expected %s, got %s", signMethod, expected, rt.LastRequest.URL.Host)
+		}
+		if host := rt.LastRequest.Header["Host"]; signMethod == "TC3-HMAC-SHA256" && (len(host) != 1 || host[0] != expected) {
+			t.Fatalf("%s: unexpected signed Host header %v", signMethod, host)
+		}
+	}
+}
+
+func TestRawBody(t *testing.T) {
+	send := func(client *common.Client) *tchttp.CommonResponse {
+		response := tchttp.NewCommonResponse()
+		if err := client.Send(newTestRequest(), response); err != nil {
+			t.Fatalf("unexpected failed on request: %+v", err)
+		}
+		return response
+	}
+	rt := &mockRT{}
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, profile.NewClientProfile())
+	client.WithHttpTransport(rt).WithReadCache(time.Minute, "RunInstances")
+	if response := send(client); response.GetRawBody() != nil {
+		t.Fatalf("raw body should not be kept by default, got %s", response.GetRawBody())
+	}
+	// the read cache keeps its own copy of the body
+	if send(client); rt.Requests != 1 {
+		t.Fatalf("unexpected requests sent, expected %d, got %d", 1, rt.Requests)
+	}
+
+	client.WithRawBody(int64(len(successResp)))
+	if response := send(client); string(response.GetRawBody()) != successResp {
+		t.Fatalf("unexpected raw body %s", response.GetRawBody())
+	}
+	client.WithRawBody(int64(len(successResp)) - 1)
+	if response := send(client); response.GetRawBody() != nil {
+		t.Fatalf("raw body larger than the limit should not be kept, got %s", response.GetRawBody())
+	}
+}
+
+func TestReadCache(t *testing.T) {
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	rt := &mockRT{RateLimitFailures: 1}
+	client.WithHttpTransport(rt).WithRawBody(1<<20).WithReadCache(time.Minute, "RunInstances")
+
+	// errors are not cached
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err == nil {
+		t.Fatalf("expected rate limit error")
+	}
+	for i := 0; i < 3; i++ {
+		response := tchttp.NewCommonResponse()
+		if err := client.Send(newTestRequest(), response); err != nil {
+			t.Fatalf("unexpected failed on request: %+v", err)
+		}
+		if string(response.GetRawBody()) != successResp {
+			t.Fatalf("unexpected response %s", response.GetRawBody())
+		}
+	}
+	if rt.Requests != 2 {
+		t.Fatalf("unexpected requests sent, expected %d, got %d", 2, rt.Requests)
+	}
+
+	request := newTestRequest()
+	if err := request.SetActionParameters(map[string]interface{}{"Limit": 1}); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	other := tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeInstances")
+	if err := client.Send(other, tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	if rt.Requests != 4 {
+		t.Fatalf("unexpected requests sent, expected %d, got %d", 4, rt.Requests)
+	}
+}
+
+func TestTimestampFunc(t *testing.T) {
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	rt := &mockRT{}
+	client.WithHttpTransport(rt).WithTimestampFunc(common.MillisecondTimestamp)
+
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	timestamp := rt.LastRequest.Header["X-TC-Timestamp"][0]
+	if len(timestamp) != 13 {
+		t.Fatalf("unexpected millisecond timestamp %s", timestamp)
+	}
+	date := time.Now().UTC().Format("2006-01-02")
+	if authorization := rt.LastRequest.Header["Authorization"][0]; !strings.Contains(authorization, "/"+date+"/cvm/tc3_request") {
+		t.Fatalf("unexpected credential scope in %s", authorization)
+	}
+
+	client.WithTimestampFunc(func(now time.Time) string { return now.Format(time.RFC3339) })
+	err := client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.InvalidTimestamp" {
+		t.Fatalf("expected ClientError.InvalidTimestamp, got %+v", err)
+	}
+}
+
+func TestSignatureV3GetQueryString(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.HttpProfile.ReqMethod = "GET"
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, prof)
+	rt := &mockRT{}
+	client.WithHttpTransport(rt)
+
+	request := tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeInstances")
+	request.GetParams()["InstanceName"] = "a b+c&d=*"
+	if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	rawQuery := rt.LastRequest.URL.RawQuery
+	if rawQuery != "InstanceName=a%20b%2Bc%26d%3D%2A" {
+		t.Fatalf("unexpected query string %s", rawQuery)
+	}
+	if rt.LastRequest.URL.Query().Get("InstanceName") != "a b+c&d=*" {
+		t.Fatalf("unexpected decoded query %s", rawQuery)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	credential := common.NewCredential("id", "key")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	rt := &mockRT{}
+	client.WithHttpTransport(rt).WithDryRun(true)
+
+	request := newTestRequest()
+	if err := request.SetActionParameters(map[string]interface{}{"InstanceCount": 1}); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	err := client.Send(request, tchttp.NewCommonResponse())
+	if err != common.ErrDryRun {
+		t.Fatalf("expected ErrDryRun, got %+v", err)
+	}
+	if rt.Requests != 0 {
+		t.Fatalf("no request should be sent in dry run mode, got %d", rt.Requests)
+	}
+	captured := client.LastDryRunRequest()
+	if captured == nil || captured.Method != "POST" || captured.URL != "https://cvm.tencentcloudapi.com/" {
+		t.Fatalf("unexpected captured request %+v", captured)
+	}
+	if captured.Header["X-TC-Action"][0] != "RunInstances" || len(captured.Header["Authorization"]) != 1 {
+		t.Fatalf("unexpected captured headers %v", captured.Header)
+	}
+	if string(captured.Body) != `{"InstanceCount":1}` {
+		t.Fatalf("unexpected captured body %s", captured.Body)
+	}
+
+	client.WithDryRun(false)
+	if err = client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil || rt.Requests != 1 {
+		t.Fatalf("request should be sent after dry run disabled, err %+v", err)
+	}
+}
+
+func TestDryRunDebugRedaction(t *testing.T) {
+	client := common.NewCommonClient(common.NewTokenCredential("AKID", "secret", "my-token"), regions.Guangzhou, profile.NewClientProfile())
+	buf := &bytes.Buffer{}
+	client.WithHttpTransport(&mockRT{}).WithDryRun(true).WithDebug(true).WithDebugWriter(buf)
+	request := tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeInstances")
+	if err := client.Send(request, tchttp.NewCommonResponse()); err != common.ErrDryRun {
+		t.Fatalf("expected ErrDryRun, got %+v", err)
+	}
+	d := buf.String()
+	if !strings.Contains(d, "[DEBUG] http dry run request = ") || !strings.Contains(d, "Authorization: ******") {
+		t.Fatalf("dry run request is not dumped to the debug writer %s", d)
+	}
+	if strings.Contains(d, "my-token") || strings.Contains(d, "TC3-HMAC-SHA256") {
+		t.Fatalf("secrets are not redacted in dump %s", d)
+	}
+	if captured := client.LastDryRunRequest(); captured == nil || len(captured.Header["X-TC-Token"]) != 1 || captured.Header["X-TC-Token"][0] != "my-token" {
+		t.Fatalf("unexpected captured request %+v", captured)
+	}
+}
+
+func TestErrorMapper(t *testing.T) {
+	errThrottled := errors.New("throttled")
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	client.WithHttpTransport(&mockRT{RateLimitFailures: 1}).WithErrorMapper(func(err *tcerr.TencentCloudSDKError) error {
+		if err.GetCode() == "RequestLimitExceeded" {
+			return fmt.Errorf("%w: %s", errThrottled, err.GetMessage())
+		}
+		return err
+	})
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); !errors.Is(err, errThrottled) {
+		t.Fatalf("expected mapped error, got %+v", err)
+	}
+
+	client.WithHttpTransport(&mockRT{NetworkFailures: 1})
+	err := client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.NetworkError" {
+		t.Fatalf("unmapped error should pass through, got %+v", err)
+	}
+}
+
+func TestBreakerState(t *testing.T) {
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	client.WithHttpTransport(&mockRT{RateLimitFailures: 2})
+	for i := 0; i < 3; i++ {
+		_ = client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	}
+	state := client.BreakerState()
+	if state.Circuit != common.CircuitDisabled || state.Successes != 1 || state.Failures != 2 || state.ConsecutiveFailures != 0 {
+		t.Fatalf("unexpected breaker state %+v", state)
+	}
+
+	prof := profile.NewClientProfile()
+	prof.RetryMode = profile.RetryModeAdaptive
+	prof.RateLimitExceededMaxRetries = 1
+	prof.RateLimitExceededRetryDuration = profile.ConstantDurationFunc(0)
+	client = common.NewCommonClient(credential, regions.Guangzhou, prof)
+	client.WithHttpTransport(&mockRT{RateLimitFailures: 1000})
+	if state = client.BreakerState(); state.Circuit != common.CircuitClosed {
+		t.Fatalf("unexpected breaker state %+v", state)
+	}
+	for i := 0; i < 101; i++ {
+		_ = client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	}
+	if state = client.BreakerState(); state.Circuit != common.CircuitOpen || state.ConsecutiveFailures != 101 {
+		t.Fatalf("unexpected breaker state %+v", state)
+	}
+}
+
+func TestResponseRetryInfo(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.NetworkFailureMaxRetries = 1
+	prof.NetworkFailureRetryDuration = profile.ConstantDurationFunc(0)
+	prof.RateLimitExceededMaxRetries = 1
+	prof.RateLimitExceededRetryDuration = profile.ConstantDurationFunc(0)
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, prof)
+
+	client.WithHttpTransport(&mockRT{})
+	response := tchttp.NewCommonResponse()
+	if err := client.Send(newTestRequest(), response); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	if response.Attempts != 0 || len(response.RetryReasons) != 0 {
+		t.Fatalf("unexpected retry info %d %v", response.Attempts, response.RetryReasons)
+	}
+
+	client.WithHttpTransport(&mockRT{NetworkFailures: 1, RateLimitFailures: 1})
+	response = tchttp.NewCommonResponse()
+	if err := client.Send(newTestRequest(), response); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	reasons := strings.Join(response.RetryReasons, ",")
+	if response.Attempts != 3 || reasons != "ClientError.NetworkError,RequestLimitExceeded" {
+		t.Fatalf("unexpected retry info %d %v", response.Attempts, response.RetryReasons)
+	}
+}
+
+// skewedServerRT rejects the requests whose timestamp is more than 5 minutes away from its clock
+type skewedServerRT struct {
+	skew     time.Duration
+	Requests int
+}
+
+func (s *skewedServerRT) RoundTrip(request *http.Request) (*http.Response, error) {
+	s.Requests++
+	serverTime := time.Now().Add(s.skew)
+	timestamp, _ := strconv.ParseInt(request.Header["X-TC-Timestamp"][0], 10, 64)
+	body := successResp
+	if d := serverTime.Unix() - timestamp; d > 300 || d < -300 {
+		body = `{"Response": {"RequestId": "", "Error": {"Code": "AuthFailure.SignatureExpire"}}}`
+	}
+	header := http.Header{}
+	header.Set("Date", serverTime.UTC().Format(http.TimeFormat))
+	return &http.Response{StatusCode: 200, Header: header, Body: ioutil.NopCloser(bytes.NewBufferString(body))}, nil
+}
+
+func TestClockSkewCorrection(t *testing.T) {
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	rt := &skewedServerRT{skew: time.Hour}
+	client.WithHttpTransport(rt)
+
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	if rt.Requests != 2 {
+		t.Fatalf("expected 1 retry with corrected timestamp, got %d requests", rt.Requests)
+	}
+
+	// the offset is cached and applied to subsequent requests
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	if rt.Requests != 3 {
+		t.Fatalf("expected no retry with cached offset, got %d requests", rt.Requests)
+	}
+}
+
+func TestSendCommon(t *testing.T) {
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, profile.NewClientProfile())
+	rt := &mockRT{}
+	client.WithHttpTransport(rt).WithCredential(credential)
+
+	raw, err := client.SendCommon("ccc", "2020-02-10", "DescribeNewFeature", map[string]interface{}{"SdkAppId": 1400000000})
+	if err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	if string(raw) != successResp {
+		t.Fatalf("unexpected response %s", raw)
+	}
+	if rt.LastRequest.Host != "ccc.tencentcloudapi.com" || rt.LastRequest.Header["X-TC-Action"][0] != "DescribeNewFeature" {
+		t.Fatalf("unexpected request %s %v", rt.LastRequest.Host, rt.LastRequest.Header)
+	}
+	if !strings.HasPrefix(rt.LastRequest.Header["Authorization"][0], "TC3-HMAC-SHA256 ") {
+		t.Fatalf("request is not signed with signature v3")
+	}
+}
+
+func TestRequestLanguage(t *testing.T) {
+	for _, signMethod := range []string{"HmacSHA256", "TC3-HMAC-SHA256"} {
+		prof := profile.NewClientProfile()
+		prof.SignMethod = signMethod
+		credential := common.NewCredential("", "")
+		client := common.NewCommonClient(credential, regions.Guangzhou, prof)
+		rt := &mockRT{}
+		client.WithHttpTransport(rt)
+
+		language := func() string {
+			if signMethod == "TC3-HMAC-SHA256" {
+				if v := rt.LastRequest.Header["X-TC-Language"]; len(v) > 0 {
+					return v[0]
+				}
+				return ""
+			}
+			body, _ := rt.LastRequest.GetBody()
+			b, _ := ioutil.ReadAll(body)
+			values, _ := url.ParseQuery(string(b))
+			return values.Get("Language")
+		}
+
+		for _, c := range []struct {
+			set      bool
+			language string
+			expected string
+		}{
+			{false, "", "zh-CN"},
+			{true, "en-US", "en-US"},
+			{true, "", ""},
+		} {
+			request := newTestRequest()
+			if c.set {
+				request.SetLanguage(c.language)
+			}
+			if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+				t.Fatalf("unexpected failed on request: %+v", err)
+			}
+			if actual := language(); actual != c.expected {
+				t.Fatalf("%s: unexpected language, expected %q, got %q", signMethod, c.expected, actual)
+			}
+		}
+	}
+}
+
+func TestCustomHeaders(t *testing.T) {
+	for _, signMethod := range []string{"HmacSHA256", "TC3-HMAC-SHA256"} {
+		prof := profile.NewClientProfile()
+		prof.SignMethod = signMethod
+		credential := common.NewCredential("", "")
+		client := common.NewCommonClient(credential, regions.Guangzhou, prof)
+		rt := &mockRT{}
+		client.WithHttpTransport(rt).WithDefaultHeaders(map[string]string{
+			"X-TC-TraceId": "default-trace",
+			"X-App":        "app",
+		})
+
+		request := newTestRequest()
+		request.SetHeader(map[string]string{
+			"X-TC-TraceId": "request-trace",
+			"X-TC-Action":  "Overridden",
+			"Host":         "evil.example.com",
+		})
+		if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("unexpected failed on request: %+v", err)
+		}
+		header := rt.LastRequest.Header
+		get := func(key string) string {
+			// signature v3 sets the headers with non canonical keys
+			if v := header[key]; len(v) > 0 {
+				return v[0]
+			}
+			return header.Get(key)
+		}
+		if get("X-TC-TraceId") != "request-trace" || get("X-App") != "app" {
+			t.Fatalf("%s: custom headers are not applied %v", signMethod, header)
+		}
+		if rt.LastRequest.URL.Host != "cvm.tencentcloudapi.com" || get("Host") == "evil.example.com" {
+			t.Fatalf("%s: reserved Host header is overridden", signMethod)
+		}
+		if action := get("X-TC-Action"); action == "Overridden" {
+			t.Fatalf("%s: reserved X-TC-Action header is overridden", signMethod)
+		}
+	}
+}
+
+// slowRT fails every request with the context error after delay or when the context is done
+type slowRT struct {
+	delay    time.Duration
+	Requests int
+}
+
+func (s *slowRT) RoundTrip(request *http.Request) (*http.Response, error) {
+	s.Requests++
+	select {
+	case <-time.After(s.delay):
+		return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewBufferString(successResp))}, nil
+	case <-request.Context().Done():
+		return nil, request.Context().Err()
+	}
+}
+
+func TestSendWithContextTimeout(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.HttpProfile.ReqTimeout = 1
+	prof.NetworkFailureMaxRetries = 3
+	prof.NetworkFailureRetryDuration = profile.ConstantDurationFunc(time.Second)
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, prof)
+
+	// the context deadline shortens the call, and it is shared by the retries
+	rt := &slowRT{delay: time.Second}
+	client.WithHttpTransport(rt)
+	ctx, cancel := common.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := client.SendWithContext(ctx, newTestRequest(), tchttp.NewCommonResponse())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %+v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond || rt.Requests != 1 {
+		t.Fatalf("retries should share the deadline, elapsed %s with %d requests", elapsed, rt.Requests)
+	}
+
+	// the context deadline lengthens the call beyond ReqTimeout
+	rt = &slowRT{delay: 1100 * time.Millisecond}
+	client.WithHttpTransport(rt)
+	ctx, cancel = common.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err = client.SendWithContext(ctx, newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+}
+
+func TestResponseInterceptor(t *testing.T) {
+	var actions []string
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	client.WithHttpTransport(&mockRT{RateLimitFailures: 1}).WithResponseInterceptor(func(action string, resp tchttp.Response) {
+		if _, ok := resp.(*tchttp.CommonResponse); !ok {
+			t.Fatalf("unexpected response type %T", resp)
+		}
+		actions = append(actions, action)
+	})
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err == nil {
+		t.Fatalf("expected error")
+	}
+	if len(actions) != 0 {
+		t.Fatalf("interceptor should not run on error, got %v", actions)
+	}
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if len(actions) != 1 || actions[0] != newTestRequest().GetAction() {
+		t.Fatalf("unexpected intercepted actions %v", actions)
+	}
+}
+
+type serverErrorRT struct {
+	Failures   int
+	RetryAfter func() string
+	Requests   int
+}
+
+func (s *serverErrorRT) RoundTrip(request *http.Request) (*http.Response, error) {
+	s.Requests++
+	if s.Requests <= s.Failures {
+		header := http.Header{}
+		header.Set("Retry-After", s.RetryAfter())
+		return &http.Response{
+			StatusCode: 503,
+			Status:     "503 Service Unavailable",
+			Header:     header,
+			Body:       ioutil.NopCloser(strings.NewReader("<html>Service Unavailable</html>")),
+		}, nil
+	}
+	return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewBufferString(successResp))}, nil
+}
+
+func TestServerErrorRetryAfter(t *testing.T) {
+	formats := map[string]func() string{
+		"seconds": func() string { return "0" },
+		"date":    func() string { return time.Now().Add(-time.Second).UTC().Format(http.TimeFormat) },
+	}
+	for name, retryAfter := range formats {
+		t.Run(name, func(t *testing.T) {
+			prof := profile.NewClientProfile()
+			prof.NetworkFailureMaxRetries = 1
+			// the Retry-After header replaces the retry duration
+			prof.NetworkFailureRetryDuration = profile.ConstantDurationFunc(time.Hour)
+			client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+			rt := &serverErrorRT{Failures: 1, RetryAfter: retryAfter}
+			client.WithHttpTransport(rt)
+
+			response := tchttp.NewCommonResponse()
+			if err := client.Send(newTestRequest(), response); err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if rt.Requests != 2 || response.Attempts != 2 || response.RetryReasons[0] != "ClientError.HttpStatusCodeError" {
+				t.Fatalf("unexpected retries: %d requests, %d attempts, %v", rt.Requests, response.Attempts, response.RetryReasons)
+			}
+		})
+	}
+}
+
+func TestServerErrorRetryAfterCapped(t *testing.T) {
+	var event common.RetryExhaustedEvent
+	prof := profile.NewClientProfile()
+	prof.NetworkFailureMaxRetries = 1
+	prof.MaxRetryAfter = 10 * time.Millisecond
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+	client.WithHttpTransport(&serverErrorRT{Failures: 2, RetryAfter: func() string { return "120" }}).
+		WithRetryExhaustedHook(func(e common.RetryExhaustedEvent) { event = e })
+
+	err := client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.HttpStatusCodeError" {
+		t.Fatalf("expected http status code error, got %+v", err)
+	}
+	if event.Attempts != 2 || event.Delay != 10*time.Millisecond {
+		t.Fatalf("unexpected retry exhausted event %+v", event)
+	}
+
+	// no retry if the delay exceeds the deadline
+	rt := &serverErrorRT{Failures: 2, RetryAfter: func() string { return "1" }}
+	prof.MaxRetryAfter = 0
+	client = common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof).WithHttpTransport(rt)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	request := newTestRequest()
+	request.SetContext(ctx)
+	if err := client.Send(request, tchttp.NewCommonResponse()); err == nil || rt.Requests != 1 {
+		t.Fatalf("expected no retry, got %d requests, %+v", rt.Requests, err)
+	}
+}
+
+func TestRequestUnsignedPayload(t *testing.T) {
+	for _, unsignedByDefault := range []bool{false, true} {
+		prof := profile.NewClientProfile()
+		prof.UnsignedPayload = unsignedByDefault
+		client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+		rt := &mockRT{}
+		client.WithHttpTransport(rt)
+
+		for _, c := range []struct {
+			set      bool
+			unsigned bool
+			expected bool
+		}{
+			{false, false, unsignedByDefault},
+			{true, true, true},
+			{true, false, false},
+		} {
+			request := newTestRequest()
+			if c.set {
+				request.SetUnsignedPayload(c.unsigned)
+			}
+			if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+				t.Fatalf("unexpected failed on request: %+v", err)
+			}
+			actual := len(rt.LastRequest.Header["X-TC-Content-SHA256"]) > 0 && rt.LastRequest.Header["X-TC-Content-SHA256"][0] == "UNSIGNED-PAYLOAD"
+			if actual != c.expected {
+				t.Fatalf("default %v, set %v %v: expected unsigned %v, got header %v", unsignedByDefault, c.set, c.unsigned, c.expected, rt.LastRequest.Header)
+			}
+		}
+	}
+}
+
+type unreachableRT struct {
+	Unreachable map[string]bool
+	Hosts       []string
+}
+
+func (s *unreachableRT) RoundTrip(request *http.Request) (*http.Response, error) {
+	s.Hosts = append(s.Hosts, request.URL.Host)
+	if s.Unreachable[request.URL.Host] {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	}
+	return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewBufferString(successResp))}, nil
+}
+
+func TestBackupDomains(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.HttpProfile.BackupDomains = []string{"backup1.example.com", "backup2.example.com"}
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+	rt := &unreachableRT{Unreachable: map[string]bool{"cvm.tencentcloudapi.com": true, "cvm.backup1.example.com": true}}
+	client.WithHttpTransport(rt)
+
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	// the domain which succeeded is remembered
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	expected := []string{"cvm.tencentcloudapi.com", "cvm.backup1.example.com", "cvm.backup2.example.com", "cvm.backup2.example.com"}
+	if strings.Join(rt.Hosts, ",") != strings.Join(expected, ",") {
+		t.Fatalf("unexpected hosts, expected %v, got %v", expected, rt.Hosts)
+	}
+
+	rt.Unreachable["cvm.backup2.example.com"] = true
+	rt.Hosts = nil
+	err := client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.NetworkError" || len(rt.Hosts) != 3 {
+		t.Fatalf("expected network error after trying every domain, got %v, %+v", rt.Hosts, err)
+	}
+}
+
+func TestRetryHook(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.NetworkFailureMaxRetries = 1
+	prof.NetworkFailureRetryDuration = profile.ConstantDurationFunc(time.Millisecond)
+	prof.RateLimitExceededMaxRetries = 1
+	prof.RateLimitExceededRetryDuration = profile.ConstantDurationFunc(2 * time.Millisecond)
+
+	type retry struct {
+		attempt int
+		reason  error
+		delay   time.Duration
+	}
+	var retries []retry
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+	client.WithHttpTransport(&mockRT{NetworkFailures: 1, RateLimitFailures: 1}).WithRetryHook(func(attempt int, reason error, nextDelay time.Duration) {
+		retries = append(retries, retry{attempt, reason, nextDelay})
+	})
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	if len(retries) != 2 {
+		t.Fatalf("expected 2 retries, got %+v", retries)
+	}
+	if !errors.As(retries[0].reason, new(retryErr)) || retries[0].attempt != 1 || retries[0].delay != time.Millisecond {
+		t.Fatalf("unexpected network failure retry %+v", retries[0])
+	}
+	if sdkErr, ok := retries[1].reason.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "RequestLimitExceeded" ||
+		retries[1].attempt != 2 || retries[1].delay != 2*time.Millisecond {
+		t.Fatalf("unexpected rate limit retry %+v", retries[1])
+	}
+}
+
+func TestMultipartRequest(t *testing.T) {
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, profile.NewClientProfile())
+	rt := &mockRT{}
+	client.WithHttpTransport(rt)
+
+	request := tchttp.NewCommonRequest("ocr", "2018-11-19", "GeneralBasicOCR")
+	if err := request.SetMultipart([]tchttp.MultipartPart{{Name: "Image", FileName: "a.png", Content: []byte("image")}}); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	contentType := rt.LastRequest.Header["Content-Type"]
+	if len(contentType) != 1 || contentType[0] != request.GetHeader()["Content-Type"] || !strings.HasPrefix(contentType[0], "multipart/form-data; boundary=") {
+		t.Fatalf("unexpected Content-Type %v", contentType)
+	}
+	body, _ := rt.LastRequest.GetBody()
+	b, _ := ioutil.ReadAll(body)
+	if !bytes.Equal(b, request.GetMultipartBody()) {
+		t.Fatalf("unexpected body %q", b)
+	}
+}
+
+type largeRequest struct {
+	*tchttp.BaseRequest
+	Filter *string `json:"Filter,omitempty" name:"Filter"`
+}
+
+func TestMaxRequestBodyBytes(t *testing.T) {
+	for _, signMethod := range []string{"HmacSHA256", "TC3-HMAC-SHA256"} {
+		prof := profile.NewClientProfile()
+		prof.SignMethod = signMethod
+		prof.HttpProfile.MaxRequestBodyBytes = 1024
+		client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+		rt := &mockRT{}
+		client.WithHttpTransport(rt)
+
+		if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("%s: unexpected failed on request: %+v", signMethod, err)
+		}
+
+		request := &largeRequest{BaseRequest: &tchttp.BaseRequest{}, Filter: common.StringPtr(strings.Repeat("x", 2048))}
+		request.Init().WithApiInfo("cvm", "2017-03-12", "DescribeInstances")
+		err := client.Send(request, tchttp.NewCommonResponse())
+		if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.RequestBodyTooLarge" {
+			t.Fatalf("%s: expected body too large error, got %+v", signMethod, err)
+		}
+		if rt.Requests != 1 {
+			t.Fatalf("%s: the oversized request should not be sent, got %d requests", signMethod, rt.Requests)
+		}
+	}
+}
+
+func TestRegionFromEnv(t *testing.T) {
+	provider := common.NewEnvProvider("TEST_SECRET_ID", "TEST_SECRET_KEY")
+	os.Setenv("TEST_SECRET_ID", "id")
+	os.Setenv("TEST_SECRET_KEY", "key")
+	defer os.Unsetenv("TEST_SECRET_ID")
+	defer os.Unsetenv("TEST_SECRET_KEY")
+
+	os.Unsetenv(common.EnvRegion)
+	// the client without a region is built for the global services
+	client, err := common.NewClientWithProviders("", provider)
+	if err != nil || client.GetRegion() != "" {
+		t.Fatalf("unexpected region %q, %+v", client.GetRegion(), err)
+	}
+
+	os.Setenv(common.EnvRegion, regions.Shanghai)
+	defer os.Unsetenv(common.EnvRegion)
+	client, err = common.NewClientWithProviders("", provider)
+	if err != nil || client.GetRegion() != regions.Shanghai {
+		t.Fatalf("expected region from env, got %q, %+v", client.GetRegion(), err)
+	}
+	rt := &mockRT{}
+	client.WithProfile(profile.NewClientProfile()).WithHttpTransport(rt)
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	if region := rt.LastRequest.Header["X-TC-Region"]; len(region) != 1 || region[0] != regions.Shanghai {
+		t.Fatalf("unexpected X-TC-Region %v", region)
+	}
+
+	// the region passed explicitly takes precedence
+	if client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, profile.NewClientProfile()); client.GetRegion() != regions.Guangzhou {
+		t.Fatalf("unexpected region %q", client.GetRegion())
+	}
+}
+
+type closableCredential struct {
+	*common.Credential
+	closed int
+}
+
+func (c *closableCredential) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestClose(t *testing.T) {
+	credential := &closableCredential{Credential: common.NewCredential("", "")}
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, profile.NewClientProfile())
+	client.WithCredential(credential).WithHttpTransport(&mockRT{})
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	_ = client.Close()
+	if credential.closed != 1 {
+		t.Fatalf("expected the credential closed once, got %d", credential.closed)
+	}
+	err := client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.ClientClosed" {
+		t.Fatalf("expected client closed error, got %+v", err)
+	}
+}
+
+func TestUnsetFieldsOmitted(t *testing.T) {
+	for _, signMethod := range []string{"HmacSHA256", "TC3-HMAC-SHA256"} {
+		prof := profile.NewClientProfile()
+		prof.SignMethod = signMethod
+		client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+		rt := &mockRT{}
+		client.WithHttpTransport(rt)
+
+		request := &largeRequest{BaseRequest: &tchttp.BaseRequest{}}
+		request.Init().WithApiInfo("cvm", "2017-03-12", "DescribeInstances")
+		if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("unexpected failed on request: %+v", err)
+		}
+		body, _ := rt.LastRequest.GetBody()
+		b, _ := ioutil.ReadAll(body)
+		// the optional field which is not set must be absent from the signed payload
+		if strings.Contains(string(b), "Filter") {
+			t.Fatalf("%s: unexpected body %s", signMethod, b)
+		}
+	}
+}
+
+// bodyRT responds every request with the status 200 and the body
+type bodyRT string
+
+func (b bodyRT) RoundTrip(request *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(strings.NewReader(string(b)))}, nil
+}
+
+func TestStrictResponseValidation(t *testing.T) {
+	prof := profile.NewClientProfile()
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof).WithHttpTransport(bodyRT(`{"Response": {"Portal": "login"}}`))
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+
+	prof.StrictResponseValidation = true
+	client = common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof).WithHttpTransport(bodyRT(`{"Response": {"Portal": "login"}}`))
+	err := client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.MissingRequestId" {
+		t.Fatalf("expected missing request id error, got %+v", err)
+	}
+	client.WithHttpTransport(bodyRT(`{"Response": {"RequestId": "req-1"}}`))
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+}
+
+func TestDisableSignatureV1(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.DisableSignatureV1 = true
+	rt := &mockRT{}
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof).WithHttpTransport(rt)
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	for _, method := range []string{"HmacSHA1", "HmacSHA256"} {
+		client.WithSignatureMethod(method)
+		err := client.Send(newTestRequest(), tchttp.NewCommonResponse())
+		if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.SignatureV1Disabled" {
+			t.Fatalf("%s: expected signature v1 disabled error, got %+v", method, err)
+		}
+	}
+	if rt.Requests != 1 {
+		t.Fatalf("the requests signed by v1 should not be sent, got %d requests", rt.Requests)
+	}
+}
+
+func TestJsonBody(t *testing.T) {
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, profile.NewClientProfile())
+	rt := &mockRT{}
+	client.WithHttpTransport(rt)
+
+	request := tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeInstances")
+	if err := request.SetJsonBody([]byte(`{"Limit": 1`)); err == nil {
+		t.Fatalf("expected invalid json error")
+	}
+	payload := []byte(`{"Limit": 1,  "Offset": 0}`)
+	if err := request.SetJsonBody(payload); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("unexpected failed on request: %+v", err)
+		}
+		body, _ := rt.LastRequest.GetBody()
+		b, _ := ioutil.ReadAll(body)
+		if !bytes.Equal(b, payload) {
+			t.Fatalf("the body should be sent as it is, got %s", b)
+		}
+	}
+}
+
+// inflightRT records the max number of the requests in flight
+type inflightRT struct {
+	mu       sync.Mutex
+	current  int
+	max      int
+	duration time.Duration
+}
+
+func (s *inflightRT) RoundTrip(request *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	s.current++
+	if s.current > s.max {
+		s.max = s.current
+	}
+	s.mu.Unlock()
+	time.Sleep(s.duration)
+	s.mu.Lock()
+	s.current--
+	s.mu.Unlock()
+	return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewBufferString(successResp))}, nil
+}
+
+func TestMaxConcurrent(t *testing.T) {
+	rt := &inflightRT{duration: 10 * time.Millisecond}
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, profile.NewClientProfile())
+	client.WithHttpTransport(rt).WithMaxConcurrent(3)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+				t.Errorf("unexpected failed on request: %+v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if rt.max != 3 {
+		t.Fatalf("expected at most 3 requests in flight, got %d", rt.max)
+	}
+
+	// waiting for a slot is canceled along with the context
+	rt.duration = 200 * time.Millisecond
+	client.WithMaxConcurrent(1)
+	go func() { _ = client.Send(newTestRequest(), tchttp.NewCommonResponse()) }()
+	time.Sleep(20 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	request := newTestRequest()
+	request.SetContext(ctx)
+	err := client.Send(request, tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.ConcurrencyLimiterError" || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected concurrency limiter error, got %+v", err)
+	}
+}
+
+// kmsSigner resolves the reference of the secret key held by a key management service
+type kmsSigner struct {
+	keys map[string]string
+}
+
+func (s *kmsSigner) HmacSHA256(key, data []byte) []byte {
+	if real, ok := s.keys[string(key)]; ok {
+		key = []byte(real)
+	}
+	return common.DefaultSigner().HmacSHA256(key, data)
+}
+
+func TestSigner(t *testing.T) {
+	timestamp := func(time.Time) string { return "1600000000" }
+	authorization := func(client *common.Client) string {
+		rt := &mockRT{}
+		client.WithHttpTransport(rt).WithTimestampFunc(timestamp)
+		if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("unexpected failed on request: %+v", err)
+		}
+		return rt.LastRequest.Header["Authorization"][0]
+	}
+
+	expected := authorization(common.NewCommonClient(common.NewCredential("AKID", "secret"), regions.Guangzhou, profile.NewClientProfile()))
+	client := common.NewCommonClient(common.NewCredential("AKID", "kms:key-1"), regions.Guangzhou, profile.NewClientProfile())
+	client.WithSigner(&kmsSigner{keys: map[string]string{"TC3kms:key-1": "TC3secret"}})
+	if actual := authorization(client); actual != expected {
+		t.Fatalf("unexpected authorization, expected %s, got %s", expected, actual)
+	}
+}
+
+func TestSignerSignatureV1(t *testing.T) {
+	signature := func(client *common.Client) string {
+		rt := &mockRT{}
+		client.WithHttpTransport(rt).WithTimestampFunc(func(time.Time) string { return "1600000000" }).WithNonceFunc(func() int { return 1 })
+		request := newTestRequest()
+		if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("unexpected failed on request: %+v", err)
+		}
+		return request.GetParams()["Signature"]
+	}
+
+	prof := profile.NewClientProfile()
+	prof.SignMethod = common.SHA256
+	expected := signature(common.NewCommonClient(common.NewCredential("AKID", "secret"), regions.Guangzhou, prof))
+	client := common.NewCommonClient(common.NewCredential("AKID", "kms:key-1"), regions.Guangzhou, prof)
+	client.WithSigner(&kmsSigner{keys: map[string]string{"kms:key-1": "secret"}})
+	if actual := signature(client); actual != expected {
+		t.Fatalf("unexpected signature, expected %s, got %s", expected, actual)
+	}
+
+	// HmacSHA1 can not be computed by the signer
+	client.WithSignatureMethod(common.SHA1)
+	err := client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.InvalidParameter" {
+		t.Fatalf("expected invalid parameter error, got %+v", err)
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	rt := &mockRT{RateLimitFailures: 1}
+	client.WithHttpTransport(rt).WithRawBody(1<<20).WithResponseCache(common.NewMemoryResponseCache(), time.Minute)
+
+	describe := func() *tchttp.CommonResponse {
+		response := tchttp.NewCommonResponse()
+		if err := client.Send(tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeInstances"), response); err != nil {
+			t.Fatalf("unexpected failed on request: %+v", err)
+		}
+		return response
+	}
+	// errors are not cached
+	if err := client.Send(tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeInstances"), tchttp.NewCommonResponse()); err == nil {
+		t.Fatalf("expected rate limit error")
+	}
+	// the cached response is a copy
+	describe().GetRawBody()[0] = 'x'
+	for i := 0; i < 3; i++ {
+		if response := describe(); string(response.GetRawBody()) != successResp {
+			t.Fatalf("unexpected response %s", response.GetRawBody())
+		}
+	}
+	if rt.Requests != 2 {
+		t.Fatalf("unexpected requests sent, expected %d, got %d", 2, rt.Requests)
+	}
+
+	// write actions are not cached
+	for i := 0; i < 2; i++ {
+		if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("unexpected failed on request: %+v", err)
+		}
+	}
+	if rt.Requests != 4 {
+		t.Fatalf("unexpected requests sent, expected %d, got %d", 4, rt.Requests)
+	}
+}
+
+func TestResponseHeadersOnError(t *testing.T) {
+	credential := common.NewCredential("", "")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	client.WithHttpTransport(&serverErrorRT{Failures: 1, RetryAfter: func() string { return "5" }})
+
+	response := tchttp.NewCommonResponse()
+	if err := client.Send(newTestRequest(), response); err == nil {
+		t.Fatalf("expected server error")
+	}
+	if v := response.GetHeaders().Get("Retry-After"); v != "5" {
+		t.Fatalf("unexpected header of error response, got %q", v)
+	}
+}
+
+func sha256hex(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+// tc3Authorization computes the authorization of signature v3 with the timestamp 1600000000 independently of the client
+func tc3Authorization(secretId, secretKey, date, service, signedHeaders, canonicalRequest string) string {
+	hmacsha256 := func(key []byte, s string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(s))
+		return h.Sum(nil)
+	}
+	scope := date + "/" + service + "/tc3_request"
+	string2sign := "TC3-HMAC-SHA256\n1600000000\n" + scope + "\n" + sha256hex(canonicalRequest)
+	key := hmacsha256(hmacsha256(hmacsha256([]byte("TC3"+secretKey), date), service), "tc3_request")
+	return "TC3-HMAC-SHA256 Credential=" + secretId + "/" + scope + ", SignedHeaders=" + signedHeaders + ", Signature=" + hex.EncodeToString(hmacsha256(key, string2sign))
+}
+
+func TestPostQueryParams(t *testing.T) {
+	credential := common.NewCredential("AKID", "secret")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	rt := &mockRT{}
+	client.WithHttpTransport(rt).WithTimestampFunc(func(time.Time) string { return "1600000000" })
+
+	request := tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeInstances")
+	if err := request.SetActionParameters(map[string]interface{}{"Limit": 10, "Name": "a b", "Zone": "ap-guangzhou-3"}); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	request.SetQueryParams("Limit", "Name")
+	if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+
+	query := "Limit=10&Name=a%20b"
+	if rt.LastRequest.URL.RawQuery != query {
+		t.Fatalf("unexpected query string %s", rt.LastRequest.URL.RawQuery)
+	}
+	body, _ := ioutil.ReadAll(rt.LastRequest.Body)
+	if string(body) != `{"Zone":"ap-guangzhou-3"}` {
+		t.Fatalf("unexpected body %s", body)
+	}
+
+	canonicalRequest := "POST\n/\n" + query + "\ncontent-type:application/json\nhost:cvm.tencentcloudapi.com\n\ncontent-type;host\n" + sha256hex(string(body))
+	expected := tc3Authorization("AKID", "secret", "2020-09-13", "cvm", "content-type;host", canonicalRequest)
+	if actual := rt.LastRequest.Header["Authorization"][0]; actual != expected {
+		t.Fatalf("unexpected authorization, expected %s, got %s", expected, actual)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	cases := []struct {
+		rt      http.RoundTripper
+		failure common.VerifyFailure
+	}{
+		{bodyRT(`{"Response": {"RequestId": "req-1", "RegionSet": []}}`), 0},
+		{bodyRT(`{"Response": {"RequestId": "req-1", "Error": {"Code": "UnauthorizedOperation"}}}`), 0},
+		{bodyRT(`{"Response": {"RequestId": "req-1", "Error": {"Code": "AuthFailure.SecretIdNotFound"}}}`), common.VerifyAuthFailure},
+		{&mockRT{NetworkFailures: 1}, common.VerifyNetworkFailure},
+	}
+	for i, c := range cases {
+		client := common.NewCommonClient(common.NewCredential("AKID", "secret"), regions.Guangzhou, profile.NewClientProfile())
+		client.WithHttpTransport(c.rt).WithResponseCache(common.NewMemoryResponseCache(), time.Minute)
+		err := client.Verify(context.Background())
+		if c.failure == 0 {
+			if err != nil {
+				t.Fatalf("case %d: unexpected error: %+v", i, err)
+			}
+			continue
+		}
+		var verifyErr *common.VerifyError
+		if !errors.As(err, &verifyErr) || verifyErr.Failure != c.failure {
+			t.Fatalf("case %d: unexpected error, expected %s, got %+v", i, c.failure, err)
+		}
+	}
+}
+
+// errorCodeRT fails the first Failures requests with the error code Code, it records the body of every request
+type errorCodeRT struct {
+	Code     string
+	Failures int
+	Bodies   []string
+}
+
+func (s *errorCodeRT) RoundTrip(request *http.Request) (*http.Response, error) {
+	body, _ := ioutil.ReadAll(request.Body)
+	s.Bodies = append(s.Bodies, string(body))
+	if len(s.Bodies) <= s.Failures {
+		resp := `{"Response": {"RequestId": "req-1", "Error": {"Code": "` + s.Code + `"}}}`
+		return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(strings.NewReader(resp))}, nil
+	}
+	return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(strings.NewReader(successResp))}, nil
+}
+
+func TestRateLimitRetryDeadline(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.RateLimitExceededMaxRetries = 2
+	prof.RateLimitExceededRetryDuration = profile.ConstantDurationFunc(time.Second)
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+	rt := &errorCodeRT{Code: "RequestLimitExceeded", Failures: 2}
+	client.WithHttpTransport(rt)
+
+	// no retry if the delay exceeds the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	request := newTestRequest()
+	request.SetContext(ctx)
+	start := time.Now()
+	err := client.Send(request, tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "RequestLimitExceeded" || len(rt.Bodies) != 1 {
+		t.Fatalf("expected no retry, got %d requests, %+v", len(rt.Bodies), err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("should not wait for the deadline, took %s", elapsed)
+	}
+}
+
+func TestRetryableErrorCodes(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.RateLimitExceededMaxRetries = 2
+	prof.RateLimitExceededRetryDuration = profile.ConstantDurationFunc(0)
+	prof.RetryableErrorCodes = []string{"InternalError"}
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+
+	rt := &errorCodeRT{Code: "InternalError", Failures: 2}
+	client.WithHttpTransport(rt)
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	if len(rt.Bodies) != 3 {
+		t.Fatalf("unexpected requests sent, expected %d, got %d", 3, len(rt.Bodies))
+	}
+	for _, body := range rt.Bodies {
+		if body == "" || body != rt.Bodies[0] {
+			t.Fatalf("unexpected body of retry %s, expected %s", body, rt.Bodies[0])
+		}
+	}
+
+	rt = &errorCodeRT{Code: "ResourceInsufficient", Failures: 1}
+	client.WithHttpTransport(rt)
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err == nil || len(rt.Bodies) != 1 {
+		t.Fatalf("unexpected retry of error code not listed, err %+v", err)
+	}
+}
+
+func TestTimeSourceAndNonce(t *testing.T) {
+	golden := map[string]string{
+		"HmacSHA256":      "uqxhilimq7x8RCGebXbOjQWwhRxRR05QJCxvMbCUQfI=",
+		"TC3-HMAC-SHA256": "TC3-HMAC-SHA256 Credential=AKIDz8krbsJ5yKBZQpn74WFkmLPx3EXAMPLE/2019-02-25/cvm/tc3_request, SignedHeaders=content-type;host, Signature=62cc1203c112368345f8b9b9a19250a256ca9852dc3b62843e7e2be838551002",
+	}
+	for signMethod, expected := range golden {
+		prof := profile.NewClientProfile()
+		prof.SignMethod = signMethod
+		client := common.NewCommonClient(common.NewCredential("AKIDz8krbsJ5yKBZQpn74WFkmLPx3EXAMPLE", "Gu5t9xGARNpq86cd98joQYCN3EXAMPLE"), regions.Guangzhou, prof)
+		rt := &mockRT{}
+		client.WithHttpTransport(rt).
+			WithTimeSource(func() time.Time { return time.Unix(1551113065, 0) }).
+			WithNonceFunc(func() int { return 11886 })
+
+		request := tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeInstances")
+		request.GetParams()["Limit"] = "1"
+		request.GetParams()["Offset"] = "0"
+		if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("%s: unexpected failed on request: %+v", signMethod, err)
+		}
+		var actual string
+		if signMethod == "TC3-HMAC-SHA256" {
+			actual = rt.LastRequest.Header["Authorization"][0]
+		} else {
+			if err := rt.LastRequest.ParseForm(); err != nil {
+				t.Fatalf("%s: unexpected error: %+v", signMethod, err)
+			}
+			actual = rt.LastRequest.PostForm.Get("Signature")
+			if nonce := rt.LastRequest.PostForm.Get("Nonce"); nonce != "11886" {
+				t.Fatalf("%s: unexpected nonce %s", signMethod, nonce)
+			}
+		}
+		if actual != expected {
+			t.Fatalf("%s: unexpected signature, expected %s, got %s", signMethod, expected, actual)
+		}
+	}
+}
+
+func TestRequestSetVersion(t *testing.T) {
+	for _, signMethod := range []string{"HmacSHA256", "TC3-HMAC-SHA256"} {
+		prof := profile.NewClientProfile()
+		prof.SignMethod = signMethod
+		client := common.NewCommonClient(common.NewCredential("AKID", "KEY"), regions.Guangzhou, prof)
+		rt := &mockRT{}
+		client.WithHttpTransport(rt)
+
+		request := tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeInstances")
+		request.SetVersion("2017-03-13")
+		if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("%s: unexpected failed on request: %+v", signMethod, err)
+		}
+		var actual string
+		if signMethod == "TC3-HMAC-SHA256" {
+			actual = rt.LastRequest.Header["X-TC-Version"][0]
+		} else {
+			if err := rt.LastRequest.ParseForm(); err != nil {
+				t.Fatalf("%s: unexpected error: %+v", signMethod, err)
+			}
+			actual = rt.LastRequest.PostForm.Get("Version")
+		}
+		if actual != "2017-03-13" {
+			t.Fatalf("%s: unexpected version %s", signMethod, actual)
+		}
+	}
+}
+
+func TestStreamResponse(t *testing.T) {
+	body := `{"Response": {"RequestId": "req-1", "Messages": []}}`
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, profile.NewClientProfile())
+	client.WithHttpTransport(bodyRT(body))
+
+	response := tchttp.NewStreamResponse()
+	if err := client.Send(newTestRequest(), response); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	defer response.GetStreamBody().Close()
+	if b, err := ioutil.ReadAll(response.GetStreamBody()); err != nil || string(b) != body {
+		t.Fatalf("unexpected stream body %s, %+v", b, err)
+	}
+}
+
+func TestMaxResponseBodyBytes(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.HttpProfile.MaxResponseBodyBytes = 10
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+	client.WithHttpTransport(bodyRT(`{"Response": {"RequestId": "req-1"}}`))
+
+	err := client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.ResponseBodyTooLarge" {
+		t.Fatalf("unexpected error %+v", err)
+	}
+}
+
+func TestDebugWriter(t *testing.T) {
+	body := `{"Response": {"RequestId": "req-1"}}`
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, profile.NewClientProfile())
+	buf := &bytes.Buffer{}
+	client.WithHttpTransport(bodyRT(body)).WithDebug(true).WithDebugWriter(buf)
+
+	response := tchttp.NewCommonResponse()
+	if err := client.Send(newTestRequest(), response); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	if response.GetRequestId() != "req-1" {
+		t.Fatalf("unexpected response %s", response.GetRawBody())
+	}
+	dump := buf.String()
+	if !strings.Contains(dump, "[DEBUG] http request = POST / HTTP/1.1") || !strings.Contains(dump, "[DEBUG] http response = HTTP/") || !strings.Contains(dump, body) {
+		t.Fatalf("unexpected dump %s", dump)
+	}
+}
+
+func TestDebugDumpResponse(t *testing.T) {
+	client := common.NewCommonClient(common.NewCredential("AKID", "secret"), regions.Guangzhou, profile.NewClientProfile())
+	buf := &bytes.Buffer{}
+	rt := &errorCodeRT{Code: "InternalError", Failures: 1}
+	client.WithHttpTransport(rt).WithDebug(true).WithDebugWriter(buf)
+
+	err := client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "InternalError" {
+		t.Fatalf("unexpected error %+v", err)
+	}
+	dump := buf.String()
+	if !strings.Contains(dump, "[DEBUG] http response = ") || !strings.Contains(dump, `"Code": "InternalError"`) {
+		t.Fatalf("unexpected dump %s", dump)
+	}
+	if strings.Contains(dump, "Signature=") || !strings.Contains(dump, "Authorization: ******") {
+		t.Fatalf("authorization is not redacted in dump %s", dump)
+	}
+	// the request body is still sent after it is dumped
+	if len(rt.Bodies) != 1 || rt.Bodies[0] == "" || !strings.Contains(dump, rt.Bodies[0]) {
+		t.Fatalf("unexpected body of request %v", rt.Bodies)
+	}
+}
+
+func TestDebugRedaction(t *testing.T) {
+	dump := func(redaction bool) string {
+		client := common.NewCommonClient(common.NewTokenCredential("AKID", "secret", "my-token"), regions.Guangzhou, profile.NewClientProfile())
+		buf := &bytes.Buffer{}
+		client.WithHttpTransport(&mockRT{}).WithDebug(true).WithDebugWriter(buf).WithDebugRedaction(redaction)
+		if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("unexpected failed on request: %+v", err)
+		}
+		return buf.String()
+	}
+	if d := dump(true); strings.Contains(d, "my-token") || strings.Contains(d, "Signature=") || !strings.Contains(d, "X-TC-Token: ******") {
+		t.Fatalf("secrets are not redacted in dump %s", d)
+	}
+	if d := dump(false); !strings.Contains(d, "my-token") || !strings.Contains(d, "Signature=") {
+		t.Fatalf("unexpected redacted dump %s", d)
+	}
+}
+
+func TestSignedHeaders(t *testing.T) {
+	credential := common.NewCredential("AKID", "secret")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	rt := &mockRT{}
+	client.WithHttpTransport(rt).WithTimestampFunc(func(time.Time) string { return "1600000000" })
+
+	request := tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeInstances")
+	request.SetHeader(map[string]string{"X-TC-TraceId": "  Trace-1 "})
+	request.SetSignedHeaders("x-tc-traceid", "X-TC-Action", "Host")
+	if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+
+	signedHeaders := "content-type;host;x-tc-action;x-tc-traceid"
+	canonicalRequest := "POST\n/\n\ncontent-type:application/json\nhost:cvm.tencentcloudapi.com\nx-tc-action:describeinstances\nx-tc-traceid:trace-1\n\n" + signedHeaders + "\n" + sha256hex("{}")
+	expected := tc3Authorization("AKID", "secret", "2020-09-13", "cvm", signedHeaders, canonicalRequest)
+	if actual := rt.LastRequest.Header["Authorization"][0]; actual != expected {
+		t.Fatalf("unexpected authorization, expected %s, got %s", expected, actual)
+	}
+
+	request = tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeInstances")
+	request.SetSignedHeaders("X-TC-Missing")
+	err := client.Send(request, tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.InvalidParameter" {
+		t.Fatalf("unexpected error %+v", err)
+	}
+}
+
+// gateRT counts the requests and holds them until release is closed or their contexts are done
+type gateRT struct {
+	requests int32
+	release  chan struct{}
+}
+
+func (s *gateRT) RoundTrip(request *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&s.requests, 1)
+	select {
+	case <-s.release:
+	case <-request.Context().Done():
+		return nil, request.Context().Err()
+	}
+	header := http.Header{}
+	header.Set("X-RateLimit-Remaining", "9")
+	return &http.Response{StatusCode: 200, Header: header, Body: ioutil.NopCloser(bytes.NewBufferString(successResp))}, nil
+}
+
+func TestSingleFlight(t *testing.T) {
+	send := func(client *common.Client, rt *gateRT, newRequest func() tchttp.Request) int32 {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				response := tchttp.NewCommonResponse()
+				if err := client.Send(newRequest(), response); err != nil {
+					t.Errorf("unexpected failed on request: %+v", err)
+					return
+				}
+				if string(response.GetRawBody()) != successResp || response.GetHeaders().Get("X-RateLimit-Remaining") != "9" {
+					t.Errorf("unexpected response %s %v", response.GetRawBody(), response.GetHeaders())
+				}
+			}()
+		}
+		time.Sleep(50 * time.Millisecond)
+		close(rt.release)
+		wg.Wait()
+		return atomic.LoadInt32(&rt.requests)
+	}
+
+	rt := &gateRT{release: make(chan struct{})}
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, profile.NewClientProfile())
+	client.WithHttpTransport(rt).WithRawBody(1 << 20).WithSingleFlight(true)
+	describe := func() tchttp.Request { return tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeInstances") }
+	if n := send(client, rt, describe); n != 1 {
+		t.Fatalf("expected 1 request for concurrent identical calls, got %d", n)
+	}
+
+	// mutations are never collapsed
+	rt = &gateRT{release: make(chan struct{})}
+	client.WithHttpTransport(rt)
+	if n := send(client, rt, func() tchttp.Request { return newTestRequest() }); n != 10 {
+		t.Fatalf("expected 10 requests for concurrent mutations, got %d", n)
+	}
+
+	rt = &gateRT{release: make(chan struct{})}
+	client.WithHttpTransport(rt).WithRawBody(1 << 20).WithSingleFlight(false)
+	if n := send(client, rt, describe); n != 10 {
+		t.Fatalf("expected 10 requests with single flight disabled, got %d", n)
+	}
+}
+
+func TestSingleFlightCancelledLeader(t *testing.T) {
+	rt := &gateRT{release: make(chan struct{})}
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, profile.NewClientProfile())
+	client.WithHttpTransport(rt).WithSingleFlight(true)
+	send := func(ctx context.Context) chan error {
+		result := make(chan error, 1)
+		go func() {
+			request := tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeInstances")
+			request.SetContext(ctx)
+			result <- client.Send(request, tchttp.NewCommonResponse())
+		}()
+		return result
+	}
+	waitRequests := func(n int32) {
+		for i := 0; atomic.LoadInt32(&rt.requests) != n; i++ {
+			if i > 100 {
+				t.Fatalf("expected %d requests, got %d", n, atomic.LoadInt32(&rt.requests))
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leader := send(leaderCtx)
+	waitRequests(1)
+	followerCtx, cancelFollower := context.WithCancel(context.Background())
+	follower := send(followerCtx)
+	waiting := send(context.Background())
+	time.Sleep(20 * time.Millisecond)
+
+	// the follower stops waiting once its own context is done
+	cancelFollower()
+	if err := <-follower; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the error of the follower context, got %+v", err)
+	}
+	// the error of the leader context is not shared, the waiting call sends its own request
+	cancelLeader()
+	if err := <-leader; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the error of the leader context, got %+v", err)
+	}
+	waitRequests(2)
+	close(rt.release)
+	if err := <-waiting; err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+}
+
+func TestRetryWithoutClientToken(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	prof := profile.NewClientProfile()
+	prof.NetworkFailureMaxRetries = 1
+	prof.NetworkFailureRetryDuration = profile.ConstantDurationFunc(0)
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+	rt := &mockRT{NetworkFailures: 1}
+	client.WithHttpTransport(rt)
+	// the requests without a ClientToken field are sent only once by default
+	if err := client.Send(tchttp.NewCommonRequest("cvm", "2017-03-12", "RunInstances"), tchttp.NewCommonResponse()); err == nil || rt.Requests != 1 {
+		t.Fatalf("unexpected %d requests, err %+v", rt.Requests, err)
+	}
+
+	prof.RetryWithoutClientToken = true
+	common.RegisterIdempotentActions("cvm", "2017-03-12", "TerminateInstances")
+	for action, warned := range map[string]bool{"RunInstances": true, "TerminateInstances": false, "DescribeInstances": false} {
+		logs.Reset()
+		rt := &mockRT{NetworkFailures: 1}
+		client.WithHttpTransport(rt)
+		if err := client.Send(tchttp.NewCommonRequest("cvm", "2017-03-12", action), tchttp.NewCommonResponse()); err != nil || rt.Requests != 2 {
+			t.Fatalf("%s: unexpected %d requests, err %+v", action, rt.Requests, err)
+		}
+		if strings.Contains(logs.String(), "non-idempotent action "+action) != warned {
+			t.Fatalf("%s: unexpected logs %q", action, logs.String())
+		}
+	}
+}
+
+func TestEndpointResolver(t *testing.T) {
+	prof := profile.NewClientProfile()
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+	rt := &unreachableRT{Unreachable: map[string]bool{}}
+	client.WithHttpTransport(rt).WithEndpointResolver(func(service, region string) string {
+		if service == "cvm" {
+			return service + "." + region + ".internal.example.com"
+		}
+		return ""
+	})
+
+	send := func(request tchttp.Request) string {
+		rt.Hosts = nil
+		if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		return strings.Join(rt.Hosts, ",")
+	}
+	if host := send(newTestRequest()); host != "cvm.ap-guangzhou.internal.example.com" {
+		t.Fatalf("unexpected host %s", host)
+	}
+	// an empty domain falls back to the default one
+	if host := send(tchttp.NewCommonRequest("ccc", "2020-02-10", "DescribeTelCdr")); host != "ccc.tencentcloudapi.com" {
+		t.Fatalf("unexpected host %s", host)
+	}
+	// the domain of the request takes precedence
+	request := newTestRequest()
+	request.SetDomain("cvm.example.com")
+	if host := send(request); host != "cvm.example.com" {
+		t.Fatalf("unexpected host %s", host)
+	}
+
+	// the backup domains are tried after the resolved domain
+	prof.HttpProfile.BackupDomains = []string{"backup.example.com"}
+	client.WithProfile(prof)
+	rt.Unreachable["cvm.ap-guangzhou.internal.example.com"] = true
+	if hosts := send(newTestRequest()); hosts != "cvm.ap-guangzhou.internal.example.com,cvm.backup.example.com" {
+		t.Fatalf("unexpected hosts %s", hosts)
+	}
+
+	// the endpoint of the profile takes precedence
+	prof.HttpProfile.Endpoint = "cvm.endpoint.example.com"
+	client.WithProfile(prof)
+	if host := send(newTestRequest()); host != "cvm.endpoint.example.com" {
+		t.Fatalf("unexpected host %s", host)
+	}
+}
+
+func TestSendStreaming(t *testing.T) {
+	next := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-TC-Action") == "DescribeFailure" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: 1\n\n"))
+		w.(http.Flusher).Flush()
+		// the second event is sent only after the first one is read by the client
+		<-next
+		_, _ = w.Write([]byte("data: 2\n\n"))
+	}))
+	defer server.Close()
+
+	prof := profile.NewClientProfile()
+	prof.HttpProfile.Scheme = "HTTP"
+	prof.HttpProfile.Endpoint = strings.TrimPrefix(server.URL, "http://")
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+
+	resp, err := client.SendStreaming(tchttp.NewCommonRequest("ccc", "2020-02-10", "DescribeEvents"))
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected headers %v", resp.Header)
+	}
+	buf := make([]byte, 64)
+	n, err := resp.Body.Read(buf)
+	if err != nil || string(buf[:n]) != "data: 1\n\n" {
+		t.Fatalf("unexpected first event %q, %+v", buf[:n], err)
+	}
+	close(next)
+	rest, err := ioutil.ReadAll(resp.Body)
+	if err != nil || string(rest) != "data: 2\n\n" {
+		t.Fatalf("unexpected second event %q, %+v", rest, err)
+	}
+
+	_, err = client.SendStreaming(tchttp.NewCommonRequest("ccc", "2020-02-10", "DescribeFailure"))
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.HttpStatusCodeError" {
+		t.Fatalf("expected http status code error, got %+v", err)
+	}
+}
+
+func TestCredentialFunc(t *testing.T) {
+	var mu sync.Mutex
+	secretId, token := "id1", ""
+	credential := common.NewCredentialFunc(func() (string, string, string) {
+		mu.Lock()
+		defer mu.Unlock()
+		return secretId, "key", token
+	})
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, profile.NewClientProfile())
+	rt := &mockRT{}
+	client.WithHttpTransport(rt).WithCredential(credential)
+
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if authorization := rt.LastRequest.Header.Get("Authorization"); !strings.Contains(authorization, "Credential=id1/") || len(rt.LastRequest.Header["X-TC-Token"]) != 0 {
+		t.Fatalf("unexpected headers %v", rt.LastRequest.Header)
+	}
+
+	// the rotated secrets are used by the next request
+	mu.Lock()
+	secretId, token = "id2", "token2"
+	mu.Unlock()
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if authorization := rt.LastRequest.Header.Get("Authorization"); !strings.Contains(authorization, "Credential=id2/") || strings.Join(rt.LastRequest.Header["X-TC-Token"], "") != "token2" {
+		t.Fatalf("unexpected headers %v", rt.LastRequest.Header)
+	}
+}
+
+func TestClientTokenGenerator(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.NetworkFailureMaxRetries = 1
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+	var generated int32
+	client.WithHttpTransport(&mockRT{}).WithClientTokenGenerator(func() string {
+		return fmt.Sprintf("token-%d", atomic.AddInt32(&generated, 1))
+	})
+
+	request := newTestRequest()
+	if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if request.ClientToken == nil || *request.ClientToken != "token-1" {
+		t.Fatalf("unexpected client token %v", request.ClientToken)
+	}
+
+	// the default generator is restored by nil
+	client.WithClientTokenGenerator(nil)
+	request = newTestRequest()
+	if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if request.ClientToken == nil || strings.HasPrefix(*request.ClientToken, "token-") || generated != 1 {
+		t.Fatalf("unexpected client token %v", request.ClientToken)
+	}
+}
+
+func TestClonedRequestClientToken(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.NetworkFailureMaxRetries = 1
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+	var generated int32
+	client.WithHttpTransport(&mockRT{}).WithClientTokenGenerator(func() string {
+		return fmt.Sprintf("token-%d", atomic.AddInt32(&generated, 1))
+	})
+
+	template := newTestRequest()
+	if err := client.Send(template, tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	clone := func(request *requestWithClientToken) *requestWithClientToken {
+		cloned := *request
+		cloned.CommonRequest = *request.CommonRequest.Clone()
+		return &cloned
+	}
+
+	// the clones of a sent template, and their clones, get their own tokens
+	first, second := clone(template), clone(template)
+	third := clone(second)
+	for i, request := range []*requestWithClientToken{first, second, third} {
+		if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if want := fmt.Sprintf("token-%d", i+2); request.ClientToken == nil || *request.ClientToken != want {
+			t.Fatalf("clone %d should get a new token %s, got %v", i, want, request.ClientToken)
+		}
+	}
+	if *template.ClientToken != "token-1" {
+		t.Fatalf("template token should be kept, got %s", *template.ClientToken)
+	}
+
+	// the token assigned by the caller is kept by the clones
+	assigned := "assigned-token"
+	template = newTestRequest()
+	template.ClientToken = &assigned
+	cloned := clone(template)
+	if err := client.Send(cloned, tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if *cloned.ClientToken != assigned {
+		t.Fatalf("assigned token should be kept, got %s", *cloned.ClientToken)
+	}
+}
+
+func TestWithRetryBudgetBeforeProfile(t *testing.T) {
+	// the budget may be set between Init and WithProfile
+	client := new(common.Client).Init(regions.Guangzhou).WithRetryBudget(0)
+	if _, enabled := client.RetryBudgetLevel(); enabled {
+		t.Fatal("retry budget should not be enabled")
+	}
+	prof := profile.NewClientProfile()
+	prof.RetryMode = profile.RetryModeAdaptive
+	client.WithProfile(prof)
+	if _, enabled := client.RetryBudgetLevel(); !enabled {
+		t.Fatal("retry budget of the adaptive retry mode should be enabled")
+	}
+}
+
+func TestWithRetryBudget(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.NetworkFailureMaxRetries = 1
+	prof.NetworkFailureRetryDuration = profile.ConstantDurationFunc(0)
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+	rt := &mockRT{NetworkFailures: 1 << 30}
+	client.WithHttpTransport(rt).WithRetryBudget(0.1).WithProfile(prof)
+
+	// the full budget allows a burst of 100 retries
+	for i := 0; i < 100; i++ {
+		_ = client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	}
+	if rt.Requests != 200 {
+		t.Fatalf("unexpected requests, expected %d, got %d", 200, rt.Requests)
+	}
+	// then the failures are returned without retrying
+	rt.Requests = 0
+	err := client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.NetworkError" || rt.Requests != 1 {
+		t.Fatalf("expected network error without retrying, got %d requests, %+v", rt.Requests, err)
+	}
+	state := client.BreakerState()
+	if count, enabled := client.RetryBudgetExhausted(); !enabled || count != 1 || state.RetryBudgetExhausted != 1 || state.Circuit != common.CircuitOpen {
+		t.Fatalf("unexpected exhausted count %d, state %+v", count, state)
+	}
+
+	// ten successful requests refill one retry
+	rt.NetworkFailures, rt.NetworkTries = 0, 0
+	for i := 0; i < 10; i++ {
+		if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+	}
+	if state := client.BreakerState(); state.Circuit != common.CircuitClosed {
+		t.Fatalf("unexpected state %+v", state)
+	}
+
+	client.WithRetryBudget(0)
+	if _, enabled := client.RetryBudgetExhausted(); enabled {
+		t.Fatalf("retry budget should be disabled with the standard retry mode")
+	}
+}
+
+func TestSignatureAuditHook(t *testing.T) {
+	client := common.NewCommonClient(common.NewCredential("secret-id", "secret-key"), regions.Guangzhou, profile.NewClientProfile())
+	var audits []string
+	client.WithHttpTransport(&mockRT{}).WithSignatureAuditHook(func(action, credentialScope, signedHeaders string) {
+		audits = append(audits, action+" "+credentialScope+" "+signedHeaders)
+	})
+	if err := client.Send(newTestRequest(), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	date := time.Now().UTC().Format("2006-01-02")
+	if len(audits) != 1 || audits[0] != "RunInstances "+date+"/cvm/tc3_request content-type;host" {
+		t.Fatalf("unexpected audits %v", audits)
+	}
+	if strings.Contains(audits[0], "secret") {
+		t.Fatalf("secret exposed to the audit hook: %s", audits[0])
+	}
+}
+
+func TestRegionalService(t *testing.T) {
+	os.Unsetenv(common.EnvRegion)
+	common.RegisterRegionalService("ccc")
+	client := common.NewCommonClient(common.NewCredential("", ""), "", profile.NewClientProfile())
+	rt := &mockRT{}
+	client.WithHttpTransport(rt)
+
+	err := client.Send(tchttp.NewCommonRequest("ccc", "2020-02-10", "DescribeTelCdr"), tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.RegionNotFound" || !strings.Contains(sdkErr.GetMessage(), "ccc") || rt.Requests != 0 {
+		t.Fatalf("expected region not found error without sending, got %d requests, %+v", rt.Requests, err)
+	}
+	// the global services are exempt
+	if err := client.Send(tchttp.NewCommonRequest("cam", "2019-01-16", "ListUsers"), tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+}
+
+func TestMaxRetryElapsedTime(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.RateLimitExceededMaxRetries = 10
+	prof.RateLimitExceededRetryDuration = profile.ConstantDurationFunc(100 * time.Millisecond)
+	prof.MaxRetryElapsedTime = 250 * time.Millisecond
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+	rt := &mockRT{RateLimitFailures: 100}
+	client.WithHttpTransport(rt)
+
+	start := time.Now()
+	err := client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "RequestLimitExceeded" {
+		t.Fatalf("expected the last error, got %+v", err)
+	}
+	// the retry which would start after 250ms is not made
+	if elapsed := time.Since(start); rt.Requests > 3 || elapsed >= 300*time.Millisecond {
+		t.Fatalf("unexpected %d requests in %v", rt.Requests, elapsed)
+	}
+}
+
+func TestRetryDecider(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.NetworkFailureMaxRetries = 1
+	prof.NetworkFailureRetryDuration = profile.ConstantDurationFunc(0)
+	prof.RateLimitExceededMaxRetries = 1
+	prof.RateLimitExceededRetryDuration = profile.ConstantDurationFunc(0)
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, prof)
+	client.WithRetryDecider(func(request tchttp.Request, resp *http.Response, err error) bool {
+		if resp == nil {
+			return request.GetAction() == "DescribeInstances"
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		return strings.Contains(string(body), "ResourceInUse")
+	})
+
+	// the error code unknown to the SDK is retried even without a ClientToken field
+	rt := &errorCodeRT{Code: "ResourceInUse", Failures: 1}
+	client.WithHttpTransport(rt)
+	if err := client.Send(tchttp.NewCommonRequest("cvm", "2017-03-12", "RunInstances"), tchttp.NewCommonResponse()); err != nil || len(rt.Bodies) != 2 {
+		t.Fatalf("unexpected %d requests, err %+v", len(rt.Bodies), err)
+	}
+	// the decider overrides the rate limit detection, the body is still parsed after being read
+	mock := &mockRT{RateLimitFailures: 1}
+	client.WithHttpTransport(mock)
+	err := client.Send(newTestRequest(), tchttp.NewCommonResponse())
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.Code != "RequestLimitExceeded" || mock.Requests != 1 {
+		t.Fatalf("unexpected %d requests, err %+v", mock.Requests, err)
+	}
+	for action, requests := range map[string]int{"DescribeInstances": 2, "RunInstances": 1} {
+		mock := &mockRT{NetworkFailures: 1}
+		client.WithHttpTransport(mock)
+		_ = client.Send(tchttp.NewCommonRequest("cvm", "2017-03-12", action), tchttp.NewCommonResponse())
+		if mock.Requests != requests {
+			t.Fatalf("%s: unexpected %d requests, expected %d", action, mock.Requests, requests)
+		}
+	}
+}
+
+type describeInstancesRequest struct {
+	*tchttp.BaseRequest
+	Limit *int64  `json:"Limit,omitempty" name:"Limit"`
+	Name  *string `json:"Name,omitempty" name:"Name"`
+}
+
+func TestRequestHttpMethod(t *testing.T) {
+	credential := common.NewCredential("AKID", "secret")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	rt := &mockRT{}
+	client.WithHttpTransport(rt).WithTimestampFunc(func(time.Time) string { return "1600000000" })
+
+	for _, method := range []string{"", "GET", "POST"} {
+		request := &describeInstancesRequest{BaseRequest: &tchttp.BaseRequest{}, Limit: common.Int64Ptr(10), Name: common.StringPtr("a b")}
+		request.Init().WithApiInfo("cvm", "2017-03-12", "DescribeInstances")
+		if method != "" {
+			request.SetHttpMethod(method)
+		}
+		if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("%s: unexpected failed on request: %+v", method, err)
+		}
+
+		// the profile default POST applies unless the request overrides it
+		expectedMethod := "POST"
+		if method == "GET" {
+			expectedMethod = "GET"
+		}
+		if rt.LastRequest.Method != expectedMethod {
+			t.Fatalf("%s: unexpected method %s", method, rt.LastRequest.Method)
+		}
+		body, _ := ioutil.ReadAll(rt.LastRequest.Body)
+		query := rt.LastRequest.URL.RawQuery
+		contentType := "application/json"
+		if expectedMethod == "GET" {
+			contentType = "application/x-www-form-urlencoded"
+			if query != "Limit=10&Name=a%20b" || len(body) != 0 {
+				t.Fatalf("%s: unexpected query %s and body %s", method, query, body)
+			}
+		} else if query != "" || string(body) != `{"Limit":10,"Name":"a b"}` {
+			t.Fatalf("%s: unexpected query %s and body %s", method, query, body)
+		}
+
+		canonicalRequest := expectedMethod + "\n/\n" + query + "\ncontent-type:" + contentType + "\nhost:cvm.tencentcloudapi.com\n\ncontent-type;host\n" + sha256hex(string(body))
+		expected := tc3Authorization("AKID", "secret", "2020-09-13", "cvm", "content-type;host", canonicalRequest)
+		if actual := rt.LastRequest.Header["Authorization"][0]; actual != expected {
+			t.Fatalf("%s: unexpected authorization, expected %s, got %s", method, expected, actual)
+		}
+	}
+
+	// the action parameters of a common request are moved into the query string as well
+	request := tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeInstances")
+	if err := request.SetActionParameters(`{"Limit": 10, "Filters": [{"Name": "zone", "Values": ["a"]}]}`); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	request.SetHttpMethod("GET")
+	if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected failed on request: %+v", err)
+	}
+	query := "Filters.0.Name=zone&Filters.0.Values.0=a&Limit=10"
+	if rt.LastRequest.URL.RawQuery != query {
+		t.Fatalf("unexpected query %s", rt.LastRequest.URL.RawQuery)
+	}
+	canonicalRequest := "GET\n/\n" + query + "\ncontent-type:application/x-www-form-urlencoded\nhost:cvm.tencentcloudapi.com\n\ncontent-type;host\n" + sha256hex("")
+	expected := tc3Authorization("AKID", "secret", "2020-09-13", "cvm", "content-type;host", canonicalRequest)
+	if actual := rt.LastRequest.Header["Authorization"][0]; actual != expected {
+		t.Fatalf("unexpected authorization, expected %s, got %s", expected, actual)
+	}
+}
+
+func TestSendValidatesRequest(t *testing.T) {
+	client := common.NewCommonClient(common.NewCredential("", ""), regions.Guangzhou, profile.NewClientProfile())
+	rt := &mockRT{}
+	client.WithHttpTransport(rt)
+
+	request := &struct {
+		*tchttp.BaseRequest
+		Limit *int64 `json:"Limit,omitempty" name:"Limit" validate:"max=100"`
+	}{BaseRequest: &tchttp.BaseRequest{}, Limit: common.Int64Ptr(101)}
+	request.Init().WithApiInfo("cvm", "2017-03-12", "DescribeInstances")
+	err := client.Send(request, tchttp.NewCommonResponse())
+	var validationErr *common.ValidationError
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.InvalidParameter" || !errors.As(err, &validationErr) {
+		t.Fatalf("unexpected error %+v", err)
+	}
+	if validationErr.Errors[0].Field != "Limit" || rt.Requests != 0 {
+		t.Fatalf("unexpected field error %+v, %d requests sent", validationErr.Errors[0], rt.Requests)
+	}
+}
+
+func TestWithExcludedQueryParams(t *testing.T) {
+	credential := common.NewCredential("AKID", "secret")
+	client := common.NewCommonClient(credential, regions.Guangzhou, profile.NewClientProfile())
+	rt := &mockRT{}
+	client.WithHttpTransport(rt).
+		WithTimestampFunc(func(time.Time) string { return "1600000000" }).
+		WithNonceFunc(func() int { return 11886 })
+
+	// Nonce is kept in the query string
+	names := []string{"Action", "Version", "Region", "RequestClient", "Timestamp"}
+	for _, c := range []struct {
+		names []string
+		query string
+	}{
+		{nil, "Limit=10"},
+		{names, "Limit=10&Nonce=11886"},
+	} {
+		client.WithExcludedQueryParams(c.names...)
+		request := &describeInstancesRequest{BaseRequest: &tchttp.BaseRequest{}, Limit: common.Int64Ptr(10)}
+		request.Init().WithApiInfo("cvm", "2017-03-12", "DescribeInstances")
+		request.SetHttpMethod("GET")
+		if err := client.Send(request, tchttp.NewCommonResponse()); err != nil {
+			t.Fatalf("unexpected failed on request: %+v", err)
+		}
+		if rt.LastRequest.URL.RawQuery != c.query {
+			t.Fatalf("unexpected query %s, expected %s", rt.LastRequest.URL.RawQuery, c.query)
+		}
+		canonicalRequest := "GET\n/\n" + c.query + "\ncontent-type:application/x-www-form-urlencoded\nhost:cvm.tencentcloudapi.com\n\ncontent-type;host\n" + sha256hex("")
+		expected := tc3Authorization("AKID", "secret", "2020-09-13", "cvm", "content-type;host", canonicalRequest)
+		if actual := rt.LastRequest.Header["Authorization"][0]; actual != expected {
+			t.Fatalf("unexpected authorization, expected %s, got %s", expected, actual)
+		}
+	}
+}
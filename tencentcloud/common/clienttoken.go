@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math/rand"
 	"reflect"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
 )
 
 const (
@@ -11,30 +13,74 @@ const (
 )
 
 func safeInjectClientToken(obj interface{}) {
-	// obj Must be struct ptr
-	getType := reflect.TypeOf(obj)
-	if getType.Kind() != reflect.Ptr || getType.Elem().Kind() != reflect.Struct {
+	injectClientToken(obj, randomClientToken)
+}
+
+// WithClientTokenGenerator replaces the generator of the ClientToken injected for the retries,
+// e.g. with a ULID or UUIDv7 generator, so that the tokens sort by time and can be correlated with the logs.
+// generate must return a unique token on every call and be safe for concurrent use.
+// Pass nil to restore the default generator, which returns a random UUID.
+func (c *Client) WithClientTokenGenerator(generate func() string) *Client {
+	c.clientTokenGenerator = generate
+	return c
+}
+
+// injectClientTokenFor injects the ClientToken of request with the generator of the client
+func (c *Client) injectClientTokenFor(request interface{}) {
+	if c.clientTokenGenerator != nil {
+		injectClientToken(request, c.clientTokenGenerator)
 		return
 	}
+	safeInjectClientToken(request)
+}
 
-	// obj Must exist named field
-	_, ok := getType.Elem().FieldByName(fieldClientToken)
+// injectClientToken sets the ClientToken field of obj to the token returned by generate,
+// unless the field is already set
+func injectClientToken(obj interface{}, generate func() string) {
+	field, ok := clientTokenField(obj)
 	if !ok {
 		return
 	}
 
-	field := reflect.ValueOf(obj).Elem().FieldByName(fieldClientToken)
+	// Set if ClientToken is nil or empty
+	if field.IsNil() || field.Elem().Len() == 0 {
+		uuidVal := generate()
+		field.Set(reflect.ValueOf(&uuidVal))
+		if request, ok := obj.(tchttp.Request); ok {
+			tchttp.SetInjectedClientToken(request, uuidVal)
+		}
+	}
+}
 
-	// field Must be string ptr
-	if field.Kind() != reflect.Ptr {
-		return
+// resetClonedClientToken clears the ClientToken field of request if it was copied by BaseRequest.Clone
+// from a request the token was injected into, so that the clone does not reuse the idempotency token
+func resetClonedClientToken(request tchttp.Request) {
+	field, ok := clientTokenField(request)
+	if ok && !field.IsNil() && tchttp.IsClonedClientToken(request, field.Elem().String()) {
+		field.Set(reflect.Zero(field.Type()))
 	}
+}
 
-	// Set if ClientToken is nil or empty
-	if field.IsNil() || (field.Elem().Kind() == reflect.String && field.Elem().Len() == 0) {
-		uuidVal := randomClientToken()
-		field.Set(reflect.ValueOf(&uuidVal))
+// clientTokenField returns the ClientToken field of obj if obj is a struct ptr with such a string ptr field
+func clientTokenField(obj interface{}) (reflect.Value, bool) {
+	// obj Must be struct ptr
+	getType := reflect.TypeOf(obj)
+	if getType == nil || getType.Kind() != reflect.Ptr || getType.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	// obj Must exist named field
+	if _, ok := getType.Elem().FieldByName(fieldClientToken); !ok {
+		return reflect.Value{}, false
+	}
+
+	field := reflect.ValueOf(obj).Elem().FieldByName(fieldClientToken)
+
+	// field Must be string ptr
+	if field.Kind() != reflect.Ptr || field.Type().Elem().Kind() != reflect.String {
+		return reflect.Value{}, false
 	}
+	return field, true
 }
 
 // randomClientToken generate random string as ClientToken
@@ -0,0 +1,49 @@
+package common
+
+import (
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+const (
+	codeSignatureExpire = "AuthFailure.SignatureExpire"
+	tplClockSkew        = "[WARN] signature expired, local clock is %s behind the server, retrying with corrected timestamp"
+)
+
+// clockSkew holds the nanoseconds which the local clock is behind the server, it is accessed atomically
+type clockSkew struct {
+	offset int64
+}
+
+// now returns the local time corrected by the clock skew detected from the server
+func (c *Client) now() time.Time {
+	return c.timeNow().Add(c.clockOffset())
+}
+
+func (c *Client) clockOffset() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.clockSkew.offset))
+}
+
+// updateClockOffset computes the offset of the local clock from the Date header of the server,
+// it is called when the signature is rejected as expired
+func (c *Client) updateClockOffset(header http.Header) {
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		return
+	}
+	offset := date.Sub(c.timeNow()).Truncate(time.Second)
+	atomic.StoreInt64(&c.clockSkew.offset, int64(offset))
+	if c.debug {
+		log.Printf(tplClockSkew, offset)
+	}
+}
+
+// isSignatureExpire reports whether err is an AuthFailure.SignatureExpire error returned by the API
+func isSignatureExpire(err error) bool {
+	sdkErr, ok := err.(*errors.TencentCloudSDKError)
+	return ok && sdkErr.Code == codeSignatureExpire
+}
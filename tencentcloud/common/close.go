@@ -0,0 +1,29 @@
+package common
+
+import (
+	"io"
+	"sync/atomic"
+
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+// Close releases the idle keep-alive connections of the transport, and closes the credential
+// if it implements io.Closer, e.g. a credential which refreshes itself in background.
+// The client is unusable after Close, Send returns an error with the code ClientError.ClientClosed.
+func (c *Client) Close() error {
+	if !atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		return nil
+	}
+	c.httpClient.CloseIdleConnections()
+	if closer, ok := c.credential.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (c *Client) checkClosed() error {
+	if atomic.LoadInt32(&c.closed) != 0 {
+		return tcerr.NewTencentCloudSDKError("ClientError.ClientClosed", "the client is closed", "")
+	}
+	return nil
+}
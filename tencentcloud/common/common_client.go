@@ -1,6 +1,8 @@
 package common
 
 import (
+	"encoding/json"
+
 	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
 	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
@@ -34,3 +36,23 @@ func (c *Client) SendOctetStream(request tchttp.Request, response tchttp.Respons
 	}
 	return c.Send(request, response)
 }
+
+// SendCommon invokes any API by service, version and action, e.g. an API not supported by the SDK yet,
+// params are marshaled as the json body, and the raw json body of the response is returned.
+// The request is signed and retried in the same way as the typed methods.
+//
+// Note: only TC3-HMAC-SHA256 signature method can be specified.
+func (c *Client) SendCommon(service, version, action string, params map[string]interface{}) (json.RawMessage, error) {
+	if c.profile.SignMethod != "TC3-HMAC-SHA256" {
+		return nil, tcerr.NewTencentCloudSDKError("ClientError", "Invalid signature method.", "")
+	}
+	request := tchttp.NewCommonRequest(service, version, action)
+	if err := request.SetActionParameters(params); err != nil {
+		return nil, err
+	}
+	response := tchttp.NewCommonResponse()
+	if err := c.sendRequest(request, response); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(response.GetRawBody()), nil
+}
@@ -0,0 +1,35 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+// WithMaxConcurrent limits the calls in flight through the client to at most n,
+// Send blocks until a slot is free or the context of the request is done.
+// Unlike WithRateLimiter, it limits the concurrency rather than the QPS. n <= 0 means unlimited.
+func (c *Client) WithMaxConcurrent(n int) *Client {
+	if n <= 0 {
+		c.inflight = nil
+		return c
+	}
+	c.inflight = make(chan struct{}, n)
+	return c
+}
+
+// acquireSlot waits for a free slot, the returned release must be called when the call finishes
+func (c *Client) acquireSlot(ctx context.Context) (release func(), err error) {
+	inflight := c.inflight
+	if inflight == nil {
+		return func() {}, nil
+	}
+	select {
+	case inflight <- struct{}{}:
+		return func() { <-inflight }, nil
+	case <-ctx.Done():
+		msg := fmt.Sprintf("Fail to wait for concurrency limiter because %s", ctx.Err())
+		return nil, tcerr.NewTencentCloudSDKErrorWithCause("ClientError.ConcurrencyLimiterError", msg, "", ctx.Err())
+	}
+}
@@ -0,0 +1,79 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ClientConfigSnapshot is a serializable view of the effective configuration of a client.
+// It is designed for comparing configurations across environments,
+// so it never contains any secret material such as SecretKey or Token.
+type ClientConfigSnapshot struct {
+	Region          string `json:"Region"`
+	Scheme          string `json:"Scheme"`
+	Endpoint        string `json:"Endpoint"`
+	RootDomain      string `json:"RootDomain"`
+	ReqMethod       string `json:"ReqMethod"`
+	ReqTimeout      int    `json:"ReqTimeout"`
+	SignMethod      string `json:"SignMethod"`
+	UnsignedPayload bool   `json:"UnsignedPayload"`
+	Language        string `json:"Language"`
+	Debug           bool   `json:"Debug"`
+
+	RetryMode                   string `json:"RetryMode"`
+	NetworkFailureMaxRetries    int    `json:"NetworkFailureMaxRetries"`
+	RateLimitExceededMaxRetries int    `json:"RateLimitExceededMaxRetries"`
+
+	// CredentialType is the go type of the credential in use, e.g. *common.Credential
+	CredentialType string `json:"CredentialType"`
+	// Providers is the names of the providers which the credential was looked up from
+	Providers []string `json:"Providers,omitempty"`
+}
+
+// String returns the snapshot as a json string, which is convenient for logging
+func (s ClientConfigSnapshot) String() string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// DumpConfig returns a redacted snapshot of the effective configuration of the client
+func (c *Client) DumpConfig() ClientConfigSnapshot {
+	snapshot := ClientConfigSnapshot{
+		Region:          c.region,
+		SignMethod:      c.signMethod,
+		UnsignedPayload: c.unsignedPayload,
+		Debug:           c.debug,
+		Providers:       c.providers,
+	}
+	if c.credential != nil {
+		snapshot.CredentialType = fmt.Sprintf("%T", c.credential)
+	}
+	if c.httpProfile != nil {
+		snapshot.Scheme = c.httpProfile.Scheme
+		snapshot.Endpoint = c.httpProfile.Endpoint
+		snapshot.RootDomain = c.httpProfile.RootDomain
+		snapshot.ReqMethod = c.httpProfile.ReqMethod
+		snapshot.ReqTimeout = c.httpProfile.ReqTimeout
+	}
+	if c.profile != nil {
+		snapshot.Language = c.profile.Language
+		snapshot.RetryMode = c.profile.RetryMode
+		snapshot.NetworkFailureMaxRetries = c.profile.NetworkFailureMaxRetries
+		snapshot.RateLimitExceededMaxRetries = c.profile.RateLimitExceededMaxRetries
+	}
+	return snapshot
+}
+
+// providerNames returns the names of provider, a provider chain is expanded to its providers
+func providerNames(provider Provider) []string {
+	if chain, ok := provider.(*ProviderChain); ok {
+		names := make([]string, 0, len(chain.Providers))
+		for _, p := range chain.Providers {
+			names = append(names, providerNames(p)...)
+		}
+		return names
+	}
+	name := fmt.Sprintf("%T", provider)
+	return []string{name[strings.LastIndex(name, ".")+1:]}
+}
@@ -0,0 +1,35 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/regions"
+)
+
+func TestDumpConfig(t *testing.T) {
+	cpf := profile.NewClientProfile()
+	cpf.HttpProfile.Endpoint = "ccc.tencentcloudapi.com"
+	cpf.NetworkFailureMaxRetries = 2
+	client := NewCommonClient(NewTokenCredential("secret-id", "secret-key", "secret-token"), regions.Guangzhou, cpf)
+
+	snapshot := client.DumpConfig()
+	if snapshot.Region != regions.Guangzhou || snapshot.Endpoint != "ccc.tencentcloudapi.com" ||
+		snapshot.NetworkFailureMaxRetries != 2 || snapshot.CredentialType != "*common.Credential" {
+		t.Fatalf("unexpected snapshot: %s", snapshot)
+	}
+	for _, secret := range []string{"secret-id", "secret-key", "secret-token"} {
+		if strings.Contains(snapshot.String(), secret) {
+			t.Fatalf("snapshot leaks secret material: %s", snapshot)
+		}
+	}
+}
+
+func TestProviderNames(t *testing.T) {
+	names := providerNames(DefaultProviderChain())
+	expected := []string{"EnvProvider", "FileProvider", "ProfileProvider", "CvmRoleProvider"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Fatalf("unexpected provider names, expected %v, got %v", expected, names)
+	}
+}
@@ -0,0 +1,26 @@
+package common
+
+import (
+	"context"
+	"time"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// SendWithContext sends request with ctx, which controls the cancellation and the deadline of the call.
+// The deadline of ctx replaces the ReqTimeout of the HttpProfile, so it can shorten or lengthen
+// a single call, and it is shared by all the retries of the call rather than reset by each of them.
+func (c *Client) SendWithContext(ctx context.Context, request tchttp.Request, response tchttp.Response) error {
+	request.SetContext(ctx)
+	return c.Send(request, response)
+}
+
+// WithTimeout returns a context for SendWithContext whose deadline is timeout from now,
+// it is the way to override the timeout of a single call, e.g. a large download:
+//
+//	ctx, cancel := common.WithTimeout(context.Background(), 10*time.Minute)
+//	defer cancel()
+//	err := client.SendWithContext(ctx, request, response)
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,41 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
+)
+
+// ErrCredentialExpired is the cause of the error returned when the credential is already expired
+// before the request is signed, check it with errors.Is
+var ErrCredentialExpired = errors.New("credential expired")
+
+// expirable is implemented by the temporary credentials which have an expired time
+type expirable interface {
+	getExpiredTime() int64
+}
+
+// checkCredentialExpired detects a credential which is already expired, e.g. handed out from a stale cache,
+// so that a clear local error is returned instead of a confusing remote auth failure
+func (c *Client) checkCredentialExpired() error {
+	cred, ok := c.credential.(expirable)
+	if !ok || !isExpired(cred) {
+		return nil
+	}
+	if c.profile.ExpiredCredentialPolicy != profile.ExpiredCredentialError {
+		c.credential.refresh()
+		if !isExpired(cred) {
+			return nil
+		}
+	}
+	expiredTime := time.Unix(cred.getExpiredTime(), 0).Format(time.RFC3339)
+	msg := fmt.Sprintf("credential %T expired at %s", c.credential, expiredTime)
+	return tcerr.NewTencentCloudSDKErrorWithCause("ClientError.CredentialExpired", msg, "", ErrCredentialExpired)
+}
+
+func isExpired(cred expirable) bool {
+	return cred.getExpiredTime() <= time.Now().Unix()
+}
@@ -0,0 +1,39 @@
+package common
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/regions"
+)
+
+func TestCheckCredentialExpired(t *testing.T) {
+	prof := profile.NewClientProfile()
+	prof.ExpiredCredentialPolicy = profile.ExpiredCredentialError
+	client := NewCommonClient(NewCredential("id", "key"), regions.Guangzhou, prof)
+	if err := client.checkCredentialExpired(); err != nil {
+		t.Fatalf("unexpected error for static credential: %+v", err)
+	}
+
+	client.WithCredential(&CvmRoleCredential{
+		tmpSecretId:  "id",
+		tmpSecretKey: "key",
+		token:        "token",
+		expiredTime:  time.Now().Add(time.Hour).Unix(),
+	})
+	if err := client.checkCredentialExpired(); err != nil {
+		t.Fatalf("unexpected error for valid credential: %+v", err)
+	}
+
+	client.WithCredential(&CvmRoleCredential{
+		tmpSecretId:  "id",
+		tmpSecretKey: "key",
+		token:        "token",
+		expiredTime:  time.Now().Add(-time.Minute).Unix(),
+	})
+	if err := client.checkCredentialExpired(); !errors.Is(err, ErrCredentialExpired) {
+		t.Fatalf("expected ErrCredentialExpired, got %+v", err)
+	}
+}
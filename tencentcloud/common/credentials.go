@@ -49,3 +49,38 @@ func (c *Credential) GetSecretId() string {
 func (c *Credential) GetToken() string {
 	return c.Token
 }
+
+// CredentialFunc is a credential whose getters call fn on every access, so that the secrets
+// are taken from any secret store and their rotation is picked up by the next request
+type CredentialFunc struct {
+	fn func() (secretId, secretKey, token string)
+}
+
+// NewCredentialFunc returns a credential backed by fn, which returns the current secret id, secret key
+// and token, the token is empty for a permanent key. fn is called several times to sign a request,
+// so it should be cheap, e.g. read the secrets cached by the secret store client, and safe for concurrent use.
+func NewCredentialFunc(fn func() (secretId, secretKey, token string)) *CredentialFunc {
+	return &CredentialFunc{fn: fn}
+}
+
+func (c *CredentialFunc) needRefresh() bool {
+	return false
+}
+
+func (c *CredentialFunc) refresh() {
+}
+
+func (c *CredentialFunc) GetSecretId() string {
+	secretId, _, _ := c.fn()
+	return secretId
+}
+
+func (c *CredentialFunc) GetSecretKey() string {
+	_, secretKey, _ := c.fn()
+	return secretKey
+}
+
+func (c *CredentialFunc) GetToken() string {
+	_, _, token := c.fn()
+	return token
+}
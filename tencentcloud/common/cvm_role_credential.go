@@ -44,11 +44,17 @@ func (c *CvmRoleCredential) needRefresh() bool {
 	return false
 }
 
+func (c *CvmRoleCredential) getExpiredTime() int64 {
+	return c.expiredTime
+}
+
 func (c *CvmRoleCredential) refresh() {
 	newCre, err := c.source.GetCredential()
 	if err != nil {
 		log.Println(err)
 		return
 	}
+	oldExpiry := c.expiredTime
 	*c = *newCre.(*CvmRoleCredential)
+	c.source.notify(oldExpiry, c.expiredTime)
 }
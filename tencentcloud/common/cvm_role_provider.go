@@ -17,6 +17,7 @@ const (
 var roleNotBound = errors.New("get cvm role name failed, Please confirm whether the role is bound")
 
 type CvmRoleProvider struct {
+	rotateNotifier
 	roleName string
 }
 
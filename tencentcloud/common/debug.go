@@ -0,0 +1,92 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"strings"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// WithDebugWriter writes the dumps of the http requests and responses in debug mode to w instead of the standard logger,
+// e.g. to a buffer in tests or a file, nil restores the standard logger. It does not enable the debug mode.
+// w must be safe for concurrent use if the client is shared by goroutines.
+func (c *Client) WithDebugWriter(w io.Writer) *Client {
+	c.debugWriter = w
+	return c
+}
+
+func (c *Client) debugDump(kind string, dump []byte) {
+	if c.debugWriter != nil {
+		fmt.Fprintf(c.debugWriter, "[DEBUG] http %s = %s\n", kind, dump)
+		return
+	}
+	log.Printf("[DEBUG] http %s = %s", kind, dump)
+}
+
+// redacted replaces the sensitive values in the dumps
+const redacted = "******"
+
+var (
+	// redactedHeaders carry the signature or the temporary credential
+	redactedHeaders = []string{"Authorization", "X-TC-Token"}
+	// redactedParams matches the Signature and Token parameters of signature v1, in the query string or the form body
+	redactedParams = regexp.MustCompile(`(?m)((?:^|[?&])(?:Signature|Token)=)[^&\s]*`)
+	// redactedFields matches the temporary credentials in the json body, e.g. the response of sts AssumeRole
+	redactedFields = regexp.MustCompile(`("(?:TmpSecretKey|SecretKey|Token)"\s*:\s*")[^"]*"`)
+)
+
+// WithDebugRedaction masks the secrets in the dumps of debug mode, i.e. the Authorization and X-TC-Token headers,
+// the Signature and Token parameters of signature v1, and the temporary credentials in the json bodies.
+// It is enabled by default, disable it only if the dumps never leave the local machine.
+func (c *Client) WithDebugRedaction(enabled bool) *Client {
+	c.debugNoRedaction = !enabled
+	return c
+}
+
+// dumpRequest dumps request as kind, e.g. request, with the secrets redacted unless the redaction is disabled
+func (c *Client) dumpRequest(request *http.Request, kind string) error {
+	dumped := *request
+	dumped.Header = request.Header.Clone()
+	if !c.debugNoRedaction {
+		for key := range dumped.Header {
+			for _, name := range redactedHeaders {
+				if strings.EqualFold(key, name) {
+					dumped.Header[key] = []string{redacted}
+				}
+			}
+		}
+	}
+	outbytes, err := httputil.DumpRequest(&dumped, true)
+	// the body is replaced by the dumped copy
+	request.Body = dumped.Body
+	if err != nil {
+		log.Printf("[ERROR] dump request failed because %s", err)
+		return err
+	}
+	c.debugDump(kind, c.redact(outbytes))
+	return nil
+}
+
+func (c *Client) redact(dump []byte) []byte {
+	if c.debugNoRedaction {
+		return dump
+	}
+	dump = redactedParams.ReplaceAll(dump, []byte("${1}"+redacted))
+	return redactedFields.ReplaceAll(dump, []byte("${1}"+redacted+`"`))
+}
+
+// dumpResponse dumps response, the body is replaced by the dumped copy so it can still be parsed.
+// The binary body is not dumped since it is streamed to the caller.
+func (c *Client) dumpResponse(response *http.Response) {
+	outbytes, err := httputil.DumpResponse(response, !tchttp.IsOctetStreamResponse(response))
+	if err != nil {
+		log.Printf("[ERROR] dump response failed because %s", err)
+		return
+	}
+	c.debugDump("response", c.redact(outbytes))
+}
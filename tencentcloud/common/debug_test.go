@@ -0,0 +1,25 @@
+package common
+
+import (
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	client := &Client{}
+	cases := map[string]string{
+		"GET /?Action=DescribeInstances&Signature=abc%3D&Token=tok HTTP/1.1":              "GET /?Action=DescribeInstances&Signature=******&Token=****** HTTP/1.1",
+		"\r\n\r\nAction=DescribeInstances&SecretId=AKID&Signature=abc&Timestamp=1":        "\r\n\r\nAction=DescribeInstances&SecretId=AKID&Signature=******&Timestamp=1",
+		"\r\n\r\nToken=tok&Version=1":                                                     "\r\n\r\nToken=******&Version=1",
+		`{"Credentials": {"Token": "tok", "TmpSecretId": "AKID", "TmpSecretKey": "key"}}`: `{"Credentials": {"Token": "******", "TmpSecretId": "AKID", "TmpSecretKey": "******"}}`,
+		"Authorization: TC3-HMAC-SHA256 Credential=AKID, SignedHeaders=content-type;host": "Authorization: TC3-HMAC-SHA256 Credential=AKID, SignedHeaders=content-type;host",
+	}
+	for dump, expected := range cases {
+		if actual := string(client.redact([]byte(dump))); actual != expected {
+			t.Fatalf("unexpected redacted dump, expected %q, got %q", expected, actual)
+		}
+	}
+	client.WithDebugRedaction(false)
+	if dump := "Signature=abc"; string(client.redact([]byte(dump))) != dump {
+		t.Fatalf("dump should not be redacted when redaction is disabled")
+	}
+}
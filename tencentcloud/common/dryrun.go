@@ -0,0 +1,73 @@
+package common
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// ErrDryRun is returned by Send in dry run mode, after the request is built and signed but not sent
+var ErrDryRun = errors.New("dry run: the request is not sent")
+
+// DryRunRequest is the final request captured in dry run mode
+type DryRunRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+type dryRun struct {
+	mu   sync.Mutex
+	last *DryRunRequest
+}
+
+// WithDryRun enables or disables dry run mode. In dry run mode, Send builds and signs the request
+// as usual, captures it instead of sending it, and returns ErrDryRun.
+// The captured request is dumped in debug mode like the sent ones, and can be retrieved by LastDryRunRequest.
+func (c *Client) WithDryRun(flag bool) *Client {
+	if flag {
+		c.dryRun = &dryRun{}
+	} else {
+		c.dryRun = nil
+	}
+	return c
+}
+
+// LastDryRunRequest returns the request captured last in dry run mode, it is nil if none is captured
+func (c *Client) LastDryRunRequest() *DryRunRequest {
+	if c.dryRun == nil {
+		return nil
+	}
+	c.dryRun.mu.Lock()
+	defer c.dryRun.mu.Unlock()
+	return c.dryRun.last
+}
+
+func (c *Client) captureDryRun(req *http.Request) error {
+	if c.debug {
+		if err := c.dumpRequest(req, "dry run request"); err != nil {
+			return err
+		}
+	}
+	captured := &DryRunRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header.Clone(),
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+		if captured.Body, err = ioutil.ReadAll(body); err != nil {
+			return err
+		}
+	}
+	c.dryRun.mu.Lock()
+	c.dryRun.last = captured
+	c.dryRun.mu.Unlock()
+	return ErrDryRun
+}
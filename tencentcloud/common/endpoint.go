@@ -0,0 +1,62 @@
+package common
+
+import (
+	"sync"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+type endpointKey struct {
+	rootDomain string
+	service    string
+}
+
+// endpointCache caches the service domains resolved by the client,
+// the lookup takes neither allocation nor string building
+type endpointCache struct {
+	mu      sync.RWMutex
+	domains map[endpointKey]string
+}
+
+// EndpointResolver returns the domain of service in region, e.g. ccc.internal.example.com,
+// an empty domain falls back to the default one, e.g. ccc.tencentcloudapi.com
+type EndpointResolver func(service, region string) string
+
+// WithEndpointResolver replaces the default service domain with the one returned by resolver,
+// e.g. for an air-gapped environment whose domains are not made up of the service and the root domain.
+// The precedence of the domain of a request is: the domain set on the request, HttpProfile.Endpoint,
+// resolver, then the default service domain. The domains of HttpProfile.BackupDomains are tried in order
+// if the resolved domain can not be connected. The resolver is called on every request, it is not cached.
+func (c *Client) WithEndpointResolver(resolver EndpointResolver) *Client {
+	c.endpointResolver = resolver
+	return c
+}
+
+// resolveDomain returns the domain of request when no domain is specified,
+// the endpoint of the HttpProfile takes precedence over the endpoint resolver and the service domain.
+func (c *Client) resolveDomain(request tchttp.Request) string {
+	if c.httpProfile.Endpoint != "" {
+		return c.httpProfile.Endpoint
+	}
+	if c.endpointResolver != nil {
+		if domain := c.endpointResolver(request.GetServiceForDomain(), c.GetRegion()); domain != "" {
+			return domain
+		}
+	}
+	if c.httpProfile.DisableEndpointCache {
+		return request.GetServiceDomain(request.GetServiceForDomain())
+	}
+	key := endpointKey{rootDomain: request.GetRootDomain(), service: request.GetServiceForDomain()}
+	cache := c.endpointCache
+	cache.mu.RLock()
+	domain, ok := cache.domains[key]
+	cache.mu.RUnlock()
+	if ok {
+		return domain
+	}
+	domain = request.GetServiceDomain(key.service)
+	cache.mu.Lock()
+	cache.domains[key] = domain
+	cache.mu.Unlock()
+	return domain
+}
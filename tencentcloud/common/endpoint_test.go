@@ -0,0 +1,44 @@
+package common
+
+import (
+	"testing"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/regions"
+)
+
+func TestResolveDomain(t *testing.T) {
+	client := NewCommonClient(NewCredential("", ""), regions.Guangzhou, profile.NewClientProfile())
+
+	request := tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeInstances")
+	if domain := client.resolveDomain(request); domain != "cvm.tencentcloudapi.com" {
+		t.Fatalf("unexpected domain %s", domain)
+	}
+	request = tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeInstances")
+	request.SetRootDomain(profile.RootDomainIntl)
+	if domain := client.resolveDomain(request); domain != "cvm.intl.tencentcloudapi.com" {
+		t.Fatalf("unexpected domain %s", domain)
+	}
+}
+
+func benchmarkResolveDomain(b *testing.B, disableCache bool) {
+	prof := profile.NewClientProfile()
+	prof.HttpProfile.DisableEndpointCache = disableCache
+	client := NewCommonClient(NewCredential("", ""), regions.Guangzhou, prof)
+	request := tchttp.NewCommonRequest("ccc", "2020-02-10", "DescribeChatMessages")
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			client.resolveDomain(request)
+		}
+	})
+}
+
+func BenchmarkResolveDomainCached(b *testing.B) {
+	benchmarkResolveDomain(b, false)
+}
+
+func BenchmarkResolveDomainUncached(b *testing.B) {
+	benchmarkResolveDomain(b, true)
+}
@@ -0,0 +1,31 @@
+package common
+
+import (
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+// ErrorMapper translates a TencentCloudSDKError into an application error,
+// e.g. a sentinel error shared by several error codes, or a wrapped error
+type ErrorMapper func(err *errors.TencentCloudSDKError) error
+
+// WithErrorMapper registers mapper which is invoked with every TencentCloudSDKError before it is returned by Send,
+// including those raised on the client side, whose codes start with ClientError.
+// The error returned by mapper is returned instead, returning the original error or nil passes it through unchanged.
+func (c *Client) WithErrorMapper(mapper ErrorMapper) *Client {
+	c.errorMapper = mapper
+	return c
+}
+
+func (c *Client) mapError(err error) error {
+	if c.errorMapper == nil {
+		return err
+	}
+	sdkErr, ok := err.(*errors.TencentCloudSDKError)
+	if !ok {
+		return err
+	}
+	if mapped := c.errorMapper(sdkErr); mapped != nil {
+		return mapped
+	}
+	return err
+}
@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
@@ -8,6 +9,11 @@ type TencentCloudSDKError struct {
 	Code      string
 	Message   string
 	RequestId string
+
+	// cause is the underlying error raised on the client side, e.g. a network failure
+	cause error
+	// detail is the raw error object returned by API
+	detail json.RawMessage
 }
 
 func (e *TencentCloudSDKError) Error() string {
@@ -25,6 +31,38 @@ func NewTencentCloudSDKError(code, message, requestId string) error {
 	}
 }
 
+// NewTencentCloudSDKErrorWithCause returns an error which wraps the underlying error cause,
+// the cause can be retrieved by errors.Unwrap, errors.Is or errors.As.
+func NewTencentCloudSDKErrorWithCause(code, message, requestId string, cause error) error {
+	return &TencentCloudSDKError{
+		Code:      code,
+		Message:   message,
+		RequestId: requestId,
+		cause:     cause,
+	}
+}
+
+// NewTencentCloudSDKErrorWithDetail returns an error which carries detail, the raw error object returned by API
+func NewTencentCloudSDKErrorWithDetail(code, message, requestId string, detail json.RawMessage) error {
+	return &TencentCloudSDKError{
+		Code:      code,
+		Message:   message,
+		RequestId: requestId,
+		detail:    detail,
+	}
+}
+
+// Detail returns the raw error object returned by API, e.g. {"Code": "...", "Message": "...", "SubCode": "..."},
+// so the fields specific to the service can be decoded by json.Unmarshal. It is nil for the errors raised on the client side.
+func (e *TencentCloudSDKError) Detail() json.RawMessage {
+	return e.detail
+}
+
+// Unwrap returns the underlying error, it is nil for the errors returned by API
+func (e *TencentCloudSDKError) Unwrap() error {
+	return e.cause
+}
+
 func (e *TencentCloudSDKError) GetCode() string {
 	return e.Code
 }
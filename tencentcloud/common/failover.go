@@ -0,0 +1,51 @@
+package common
+
+import (
+	"errors"
+	"net"
+	"sync/atomic"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// sendWithFailover sends request to the service domain made up with the root domains in turn,
+// starting from the one which succeeded last time, until the request reaches a server.
+func (c *Client) sendWithFailover(request tchttp.Request, response tchttp.Response) (err error) {
+	rootDomain := request.GetRootDomain()
+	if rootDomain == "" {
+		rootDomain = tchttp.RootDomain
+	}
+	rootDomains := append([]string{rootDomain}, c.httpProfile.BackupDomains...)
+	// the domain resolved by the client is tried first, it is made up of the first root domain
+	// unless it is returned by the endpoint resolver
+	primary := request.GetDomain()
+	service := request.GetServiceForDomain()
+	preferred := int(atomic.LoadInt32(&c.preferredDomain)) % len(rootDomains)
+	for i := range rootDomains {
+		idx := (preferred + i) % len(rootDomains)
+		if idx == 0 {
+			request.SetDomain(primary)
+		} else {
+			request.SetDomain(service + "." + rootDomains[idx])
+		}
+		err = c.send(request, response)
+		if !isUnreachable(err) || request.GetContext().Err() != nil {
+			if err == nil && idx != preferred {
+				atomic.StoreInt32(&c.preferredDomain, int32(idx))
+			}
+			return err
+		}
+	}
+	return err
+}
+
+// isUnreachable returns whether err is a dns or connection failure,
+// that is to say the request has never reached the server and it is safe to send it again
+func isUnreachable(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
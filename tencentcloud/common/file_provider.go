@@ -0,0 +1,149 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+// CredentialFileNotFoundError is returned by FileProvider when the credentials file does not exist,
+// the ProviderChain skips the provider on this error and tries the next one
+type CredentialFileNotFoundError struct {
+	Path string
+}
+
+func (e *CredentialFileNotFoundError) Error() string {
+	return "could not find credentials file " + e.Path
+}
+
+// FileProvider reads the credential from the section named profileName of an INI or JSON file.
+//
+// INI file:
+//
+//	[default]
+//	secret_id = AKID********
+//	secret_key = ********
+//	region = ap-guangzhou
+//
+// JSON file:
+//
+//	{"default": {"secret_id": "AKID********", "secret_key": "********", "region": "ap-guangzhou"}}
+//
+// The file is treated as JSON when it has a .json extension or its content starts with '{'.
+// An optional "token" key is used as the temporary credential token.
+type FileProvider struct {
+	path        string
+	profileName string
+}
+
+// fileProfile is the content of a profile in the credentials file
+type fileProfile struct {
+	SecretId  string `json:"secret_id"`
+	SecretKey string `json:"secret_key"`
+	Token     string `json:"token"`
+	Region    string `json:"region"`
+}
+
+// DefaultFileProvider return a File provider whose path and profile name are resolved
+// in the same way as DefaultProfileProvider
+func DefaultFileProvider() *FileProvider {
+	return &FileProvider{}
+}
+
+// NewFileProvider return a File provider which reads the profile profileName of the file path,
+// the empty profileName means the value of TENCENTCLOUD_PROFILE or default
+func NewFileProvider(path, profileName string) *FileProvider {
+	return &FileProvider{path: path, profileName: profileName}
+}
+
+func (p *FileProvider) getPath() (string, error) {
+	if p.path != "" {
+		return p.path, nil
+	}
+	if path, ok := os.LookupEnv(EnvCredentialFile); ok {
+		if path == "" {
+			return "", tcerr.NewTencentCloudSDKError(creErr, "Environment variable '"+EnvCredentialFile+"' cannot be empty", "")
+		}
+		return path, nil
+	}
+	path := getCredentialsFilePath()
+	if path == "" {
+		return "", &CredentialFileNotFoundError{}
+	}
+	return path, nil
+}
+
+func (p *FileProvider) getProfileName() string {
+	return (&ProfileProvider{profileName: p.profileName}).getProfileName()
+}
+
+func (p *FileProvider) loadProfile() (*fileProfile, error) {
+	path, err := p.getPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, &CredentialFileNotFoundError{Path: path}
+		}
+		return nil, tcerr.NewTencentCloudSDKError(creErr, "Failed to read credentials file,"+err.Error(), "")
+	}
+
+	name := p.getProfileName()
+	if strings.EqualFold(filepath.Ext(path), ".json") || bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		profiles := map[string]*fileProfile{}
+		if err := json.Unmarshal(data, &profiles); err != nil {
+			return nil, tcerr.NewTencentCloudSDKError(creErr, "Failed to parse credentials file "+path+","+err.Error(), "")
+		}
+		profile, ok := profiles[name]
+		if !ok || profile == nil {
+			return nil, tcerr.NewTencentCloudSDKError(creErr, "Failed to find profile \""+name+"\" in profile file "+path, "")
+		}
+		return profile, nil
+	}
+
+	cfg, err := parse(path)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.has(name) {
+		return nil, tcerr.NewTencentCloudSDKError(creErr, "Failed to find profile \""+name+"\" in profile file "+path, "")
+	}
+	s := cfg.section(name)
+	return &fileProfile{
+		SecretId:  s.key("secret_id").string(),
+		SecretKey: s.key("secret_key").string(),
+		Token:     s.key("token").string(),
+		Region:    s.key("region").string(),
+	}, nil
+}
+
+func (p *FileProvider) GetCredential() (CredentialIface, error) {
+	profile, err := p.loadProfile()
+	if err != nil {
+		return nil, err
+	}
+	if profile.SecretId == "" || profile.SecretKey == "" {
+		return nil, tcerr.NewTencentCloudSDKError(creErr, "Failed to parse profile file,please confirm whether it contains \"secret_id\" and \"secret_key\" in section: \""+p.getProfileName()+"\" ", "")
+	}
+	return &Credential{
+		SecretId:  profile.SecretId,
+		SecretKey: profile.SecretKey,
+		Token:     profile.Token,
+	}, nil
+}
+
+// GetRegion returns the optional region of the profile, empty if it is not set
+func (p *FileProvider) GetRegion() (string, error) {
+	profile, err := p.loadProfile()
+	if err != nil {
+		return "", err
+	}
+	return profile.Region, nil
+}
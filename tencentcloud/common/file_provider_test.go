@@ -0,0 +1,78 @@
+package common
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeCredentialsFile(t *testing.T, name, content string) string {
+	dir, err := ioutil.TempDir("", "tencentcloud")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFileProvider_GetCredential(t *testing.T) {
+	iniPath := writeCredentialsFile(t, "credentials", "[default]\nsecret_id = ini-id\nsecret_key = ini-key\nregion = ap-guangzhou\n")
+	jsonPath := writeCredentialsFile(t, "credentials.json", `{"default": {"secret_id": "json-id", "secret_key": "json-key"}, "prod": {"secret_id": "prod-id", "secret_key": "prod-key", "token": "prod-token", "region": "ap-beijing"}}`)
+	defer os.RemoveAll(filepath.Dir(iniPath))
+	defer os.RemoveAll(filepath.Dir(jsonPath))
+
+	tests := []struct {
+		name       string
+		provider   *FileProvider
+		wantId     string
+		wantToken  string
+		wantRegion string
+		wantErr    bool
+	}{
+		{"ini", NewFileProvider(iniPath, ""), "ini-id", "", "ap-guangzhou", false},
+		{"json default", NewFileProvider(jsonPath, ""), "json-id", "", "", false},
+		{"json profile", NewFileProvider(jsonPath, "prod"), "prod-id", "prod-token", "ap-beijing", false},
+		{"missing profile", NewFileProvider(jsonPath, "staging"), "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.provider.GetCredential()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetCredential() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.GetSecretId() != tt.wantId || got.GetToken() != tt.wantToken {
+				t.Fatalf("GetCredential() got = %v/%v, want %v/%v", got.GetSecretId(), got.GetToken(), tt.wantId, tt.wantToken)
+			}
+			region, err := tt.provider.GetRegion()
+			if err != nil || region != tt.wantRegion {
+				t.Fatalf("GetRegion() got = %v, %v, want %v", region, err, tt.wantRegion)
+			}
+		})
+	}
+}
+
+func TestFileProvider_NotFound(t *testing.T) {
+	path := filepath.Join(os.TempDir(), "tencentcloud-does-not-exist", "credentials")
+	_, err := NewFileProvider(path, "").GetCredential()
+	var notFound *CredentialFileNotFoundError
+	if !errors.As(err, &notFound) || notFound.Path != path {
+		t.Fatalf("expected CredentialFileNotFoundError, got %v", err)
+	}
+
+	os.Setenv("TENCENTCLOUD_SECRET_ID", "env-id")
+	os.Setenv("TENCENTCLOUD_SECRET_KEY", "env-key")
+	defer os.Unsetenv("TENCENTCLOUD_SECRET_ID")
+	defer os.Unsetenv("TENCENTCLOUD_SECRET_KEY")
+	cred, err := NewProviderChain([]Provider{NewFileProvider(path, ""), DefaultEnvProvider()}).GetCredential()
+	if err != nil || cred.GetSecretId() != "env-id" {
+		t.Fatalf("expected the chain to skip the missing file, got %v, %v", cred, err)
+	}
+}
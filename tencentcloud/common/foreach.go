@@ -0,0 +1,102 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+// BatchError collects the errors of the calls made by ForEach, see Unwrap to iterate them
+type BatchError struct {
+	// Errors is aligned with the inputs, Errors[i] is nil if the i-th call succeeded
+	Errors []error
+}
+
+func (e *BatchError) Error() string {
+	failed := 0
+	var first error
+	for _, err := range e.Errors {
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			failed++
+		}
+	}
+	return fmt.Sprintf("%d of %d calls failed, the first error: %s", failed, len(e.Errors), first)
+}
+
+// Unwrap returns the errors of the failed calls in order, like the error returned by errors.Join,
+// so that errors.Is and errors.As match any of them since Go 1.20. Each of them is a *TencentCloudSDKError,
+// the one which is not, e.g. context.Canceled of a call never started, is wrapped with the code
+// ClientError.BatchCallError and kept as its cause. To handle every failure:
+//
+//	for _, err := range batchErr.Unwrap() {
+//		sdkErr := err.(*errors.TencentCloudSDKError)
+//		log.Printf("%s: %s", sdkErr.GetCode(), sdkErr.GetMessage())
+//	}
+func (e *BatchError) Unwrap() []error {
+	var errs []error
+	for i, err := range e.Errors {
+		if err == nil {
+			continue
+		}
+		if _, ok := err.(*tcerr.TencentCloudSDKError); !ok {
+			msg := fmt.Sprintf("Call %d of the batch failed because %s", i, err)
+			err = tcerr.NewTencentCloudSDKErrorWithCause("ClientError.BatchCallError", msg, "", err)
+		}
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// ForEach calls fn for each index in [0, n) with at most concurrency calls running at the same time.
+// Since Go generics are not available, fn reads its input and stores its result by the index,
+// e.g. into a slice allocated with length n, so the input order is preserved.
+//
+// ForEach waits until all the calls return. It returns nil if all the calls succeeded,
+// otherwise a *BatchError which holds the error of each call. If failFast is true, the context
+// passed to fn is cancelled on the first error and the calls not started yet fail with the context error.
+func ForEach(ctx context.Context, n int, concurrency int, failFast bool, fn func(ctx context.Context, i int) error) error {
+	if concurrency <= 0 || concurrency > n {
+		concurrency = n
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	indexes := make(chan int)
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					continue
+				}
+				if err := fn(ctx, i); err != nil {
+					errs[i] = err
+					if failFast {
+						cancel()
+					}
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return &BatchError{Errors: errs}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+func TestForEach(t *testing.T) {
+	inputs := []int{5, 3, 1, 4, 2}
+	results := make([]int, len(inputs))
+	var running, peak int32
+	err := ForEach(context.Background(), len(inputs), 2, false, func(ctx context.Context, i int) error {
+		cur := atomic.AddInt32(&running, 1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if cur <= old || atomic.CompareAndSwapInt32(&peak, old, cur) {
+				break
+			}
+		}
+		time.Sleep(time.Duration(inputs[i]) * time.Millisecond)
+		results[i] = inputs[i] * 10
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	for i := range inputs {
+		if results[i] != inputs[i]*10 {
+			t.Fatalf("unexpected results %v", results)
+		}
+	}
+	if peak > 2 {
+		t.Fatalf("concurrency exceeded, peak %d", peak)
+	}
+}
+
+func TestForEachCollectsErrors(t *testing.T) {
+	failure := errors.New("failure")
+	err := ForEach(context.Background(), 4, 2, false, func(ctx context.Context, i int) error {
+		if i%2 == 1 {
+			return failure
+		}
+		return nil
+	})
+	batchErr, ok := err.(*BatchError)
+	if !ok {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	for i, e := range batchErr.Errors {
+		if (i%2 == 1) != (e == failure) {
+			t.Fatalf("unexpected errors %v", batchErr.Errors)
+		}
+	}
+}
+
+func TestForEachFailFast(t *testing.T) {
+	failure := errors.New("failure")
+	var calls int32
+	err := ForEach(context.Background(), 100, 1, true, func(ctx context.Context, i int) error {
+		atomic.AddInt32(&calls, 1)
+		if i == 1 {
+			return failure
+		}
+		return nil
+	})
+	batchErr, ok := err.(*BatchError)
+	if !ok || batchErr.Errors[1] != failure {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if calls != 2 || batchErr.Errors[2] != context.Canceled {
+		t.Fatalf("remaining calls should be cancelled, %d calls made, errors %v", calls, batchErr.Errors[:3])
+	}
+}
+
+func TestBatchErrorUnwrap(t *testing.T) {
+	apiErr := tcerr.NewTencentCloudSDKError("LimitExceeded", "quota exceeded", "req-1")
+	err := ForEach(context.Background(), 3, 1, false, func(ctx context.Context, i int) error {
+		switch i {
+		case 0:
+			return apiErr
+		case 2:
+			return context.DeadlineExceeded
+		}
+		return nil
+	})
+	batchErr, ok := err.(*BatchError)
+	if !ok {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	errs := batchErr.Unwrap()
+	if len(errs) != 2 || errs[0] != apiErr {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	var sdkErr *tcerr.TencentCloudSDKError
+	if !errors.As(errs[1], &sdkErr) || sdkErr.GetCode() != "ClientError.BatchCallError" || !errors.Is(errs[1], context.DeadlineExceeded) {
+		t.Fatalf("unexpected error %+v", errs[1])
+	}
+	// the joined errors are matched by errors.Is and errors.As
+	if !errors.Is(err, apiErr) || !errors.Is(err, context.DeadlineExceeded) || !errors.As(err, &sdkErr) || sdkErr != apiErr {
+		t.Fatalf("joined errors are not matched, %+v", err)
+	}
+}
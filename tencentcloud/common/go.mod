@@ -1,3 +1,5 @@
 module github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common
 
 go 1.14
+
+require golang.org/x/time v0.0.0-20210220033141-f8bda1e9f3ba
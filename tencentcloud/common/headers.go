@@ -0,0 +1,60 @@
+package common
+
+import (
+	"log"
+	"strings"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+const tplReservedHeader = "[WARN] header %s is reserved, ignored"
+
+// reservedHeaders are set by the SDK itself, so they can not be overridden by custom headers
+var reservedHeaders = map[string]bool{
+	"authorization":       true,
+	"host":                true,
+	"content-type":        true,
+	"content-length":      true,
+	"x-tc-action":         true,
+	"x-tc-version":        true,
+	"x-tc-timestamp":      true,
+	"x-tc-region":         true,
+	"x-tc-token":          true,
+	"x-tc-requestclient":  true,
+	"x-tc-language":       true,
+	"x-tc-content-sha256": true,
+}
+
+func isReservedHeader(key string) bool {
+	return reservedHeaders[strings.ToLower(key)]
+}
+
+// WithDefaultHeaders sets the custom headers applied to every request, e.g. X-TC-TraceId for correlation,
+// the headers set by request.SetHeader take precedence. The reserved headers are ignored, which are
+// Authorization, Host, Content-Type, Content-Length, X-TC-Action, X-TC-Version, X-TC-Timestamp,
+// X-TC-Region, X-TC-Token, X-TC-RequestClient, X-TC-Language and X-TC-Content-SHA256.
+func (c *Client) WithDefaultHeaders(header map[string]string) *Client {
+	c.defaultHeaders = header
+	return c
+}
+
+// customHeaders merges the default headers of the client and the headers of request,
+// with the reserved headers dropped
+func (c *Client) customHeaders(request tchttp.Request) map[string]string {
+	if len(c.defaultHeaders) == 0 && len(request.GetHeader()) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(c.defaultHeaders)+len(request.GetHeader()))
+	for _, h := range []map[string]string{c.defaultHeaders, request.GetHeader()} {
+		for k, v := range h {
+			if isReservedHeader(k) {
+				if c.debug {
+					log.Printf(tplReservedHeader, k)
+				}
+				continue
+			}
+			headers[k] = v
+		}
+	}
+	return headers
+}
@@ -1,15 +1,33 @@
 package common
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strconv"
+	"strings"
+
 	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
 )
 
 const (
 	octetStream = "application/octet-stream"
+	formData    = "multipart/form-data"
 )
 
+// MultipartPart is a part of the multipart/form-data body,
+// it is a file part if FileName is not empty, otherwise a field
+type MultipartPart struct {
+	Name     string
+	FileName string
+	// ContentType of the file part, default value is application/octet-stream
+	ContentType string
+	Content     []byte
+}
+
 type actionParameters map[string]interface{}
 
 type CommonRequest struct {
@@ -17,6 +35,12 @@ type CommonRequest struct {
 	// custom header, may be overwritten
 	header map[string]string
 	actionParameters
+	// jsonBody is sent as it is instead of the marshalled actionParameters
+	jsonBody []byte
+	// queryParams are the names of the action parameters sent in the query string of a POST request
+	queryParams []string
+	// signedHeaders are the names of the headers signed by signature v3 besides Content-Type and Host
+	signedHeaders []string
 }
 
 func NewCommonRequest(service, version, action string) (request *CommonRequest) {
@@ -82,6 +106,41 @@ func (cr *CommonRequest) GetHeader() map[string]string {
 	return cr.header
 }
 
+// Clone returns a copy of the request which shares neither the params, the header
+// nor the action parameters with it, see BaseRequest.Clone. The nested maps and slices
+// of the action parameters are copied as well, the bodies set by SetOctetStreamParameters,
+// SetMultipart and SetJsonBody are shared since they are never modified.
+func (cr *CommonRequest) Clone() *CommonRequest {
+	clone := *cr
+	clone.BaseRequest = cr.BaseRequest.Clone()
+	clone.header = copyStringMap(cr.header)
+	if cr.actionParameters != nil {
+		clone.actionParameters = copyValue(map[string]interface{}(cr.actionParameters)).(map[string]interface{})
+	}
+	clone.queryParams = append([]string(nil), cr.queryParams...)
+	clone.signedHeaders = append([]string(nil), cr.signedHeaders...)
+	return &clone
+}
+
+// copyValue copies the maps and slices decoded from json, the other values are immutable or shared
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			copied[k] = copyValue(value)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, value := range v {
+			copied[i] = copyValue(value)
+		}
+		return copied
+	}
+	return v
+}
+
 // SetOctetStreamParameters set request body to your data, and set head Content-Type to application/octet-stream
 // note: you could not call SetActionParameters and SetOctetStreamParameters on the same request
 func (cr *CommonRequest) SetOctetStreamParameters(header map[string]string, body []byte) {
@@ -103,6 +162,185 @@ func (cr *CommonRequest) GetOctetStreamBody() []byte {
 	}
 }
 
+// SetMultipart set request body to the multipart/form-data encoded parts, and set head Content-Type
+// with the boundary of the body, which is signed and sent as it is.
+// note: you could not call SetMultipart and SetActionParameters or SetOctetStreamParameters on the same request
+func (cr *CommonRequest) SetMultipart(parts []MultipartPart) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for _, part := range parts {
+		var err error
+		if part.FileName == "" {
+			err = writer.WriteField(part.Name, string(part.Content))
+		} else {
+			contentType := part.ContentType
+			if contentType == "" {
+				contentType = octetStream
+			}
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(part.Name), escapeQuotes(part.FileName)))
+			header.Set("Content-Type", contentType)
+			var w io.Writer
+			if w, err = writer.CreatePart(header); err == nil {
+				_, err = w.Write(part.Content)
+			}
+		}
+		if err != nil {
+			msg := fmt.Sprintf("Fail to write multipart part %s, because: %s", part.Name, err)
+			return tcerr.NewTencentCloudSDKError("ClientError.InvalidParameter", msg, "")
+		}
+	}
+	if err := writer.Close(); err != nil {
+		msg := fmt.Sprintf("Fail to write multipart body, because: %s", err)
+		return tcerr.NewTencentCloudSDKError("ClientError.InvalidParameter", msg, "")
+	}
+	if cr.header == nil {
+		cr.header = map[string]string{}
+	}
+	cr.header["Content-Type"] = writer.FormDataContentType()
+	cr.actionParameters = map[string]interface{}{"MultipartBody": body.Bytes()}
+	return nil
+}
+
+func (cr *CommonRequest) IsMultipart() bool {
+	if !strings.HasPrefix(cr.header["Content-Type"], formData) {
+		return false
+	}
+	_, ok := cr.actionParameters["MultipartBody"].([]byte)
+	return ok
+}
+
+func (cr *CommonRequest) GetMultipartBody() []byte {
+	if cr.IsMultipart() {
+		return cr.actionParameters["MultipartBody"].([]byte)
+	}
+	return nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+// SetJsonBody set request body to the pre-serialized json body, which is signed and sent as it is
+// without marshalling, so a body can be reused by the repeated calls. The body must be a valid json.
+// note: the body must not be modified before the request is sent
+func (cr *CommonRequest) SetJsonBody(body []byte) error {
+	if !json.Valid(body) {
+		msg := fmt.Sprintf("Invalid json body: %s", body)
+		return tcerr.NewTencentCloudSDKError("ClientError.ParseJsonError", msg, "")
+	}
+	cr.jsonBody = body
+	return nil
+}
+
+// GetJsonBody returns the body set by SetJsonBody, nil if it is not set
+func (cr *CommonRequest) GetJsonBody() []byte {
+	return cr.jsonBody
+}
+
+// SetQueryParams designates the action parameters named names to be sent in the query string
+// rather than the json body of a POST request, both the query string and the body are signed.
+// note: it takes no effect on the body set by SetJsonBody, SetOctetStreamParameters or SetMultipart
+func (cr *CommonRequest) SetQueryParams(names ...string) {
+	cr.queryParams = names
+}
+
+// GetQueryParams returns the designated action parameters which are set, a string is kept as it is,
+// the other values are formatted as json, e.g. 10 or true
+func (cr *CommonRequest) GetQueryParams() (map[string]string, error) {
+	params := make(map[string]string, len(cr.queryParams))
+	for _, name := range cr.queryParams {
+		value, ok := cr.actionParameters[name]
+		if !ok {
+			continue
+		}
+		if s, ok := value.(string); ok {
+			params[name] = s
+			continue
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			msg := fmt.Sprintf("Fail to format query parameter %s, because: %s", name, err)
+			return nil, tcerr.NewTencentCloudSDKError("ClientError.InvalidParameter", msg, "")
+		}
+		params[name] = string(b)
+	}
+	return params, nil
+}
+
+// GetFlatParams returns the action parameters flattened as the query string of a GET request,
+// e.g. {"Filters": [{"Name": "zone"}]} is flattened to Filters.0.Name=zone.
+// note: it takes no effect on the body set by SetJsonBody, SetOctetStreamParameters or SetMultipart
+func (cr *CommonRequest) GetFlatParams() (map[string]string, error) {
+	// the parameters are normalized by json, so that only the types decoded by json are flattened
+	b, err := json.Marshal(cr.actionParameters)
+	if err != nil {
+		msg := fmt.Sprintf("Fail to format action parameters, because: %s", err)
+		return nil, tcerr.NewTencentCloudSDKError("ClientError.InvalidParameter", msg, "")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var value interface{}
+	if err = decoder.Decode(&value); err != nil {
+		msg := fmt.Sprintf("Fail to format action parameters, because: %s", err)
+		return nil, tcerr.NewTencentCloudSDKError("ClientError.InvalidParameter", msg, "")
+	}
+	params := make(map[string]string)
+	flatValue(params, "", value)
+	return params, nil
+}
+
+func flatValue(params map[string]string, key string, value interface{}) {
+	prefix := key
+	if prefix != "" {
+		prefix += "."
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, element := range v {
+			flatValue(params, prefix+name, element)
+		}
+	case []interface{}:
+		for i, element := range v {
+			flatValue(params, prefix+strconv.Itoa(i), element)
+		}
+	case string:
+		params[key] = v
+	case json.Number:
+		params[key] = v.String()
+	case bool:
+		params[key] = strconv.FormatBool(v)
+	}
+}
+
+// SetSignedHeaders adds the headers named names to the signed headers of signature v3, which are
+// Content-Type and Host by default. A name can be any header of the request, either set by SetHeader
+// or by the SDK, e.g. X-TC-Action or X-TC-Content-SHA256, the names are case insensitive.
+// The request fails with ClientError.InvalidParameter if a header is missing.
+func (cr *CommonRequest) SetSignedHeaders(names ...string) {
+	cr.signedHeaders = names
+}
+
+func (cr *CommonRequest) GetSignedHeaders() []string {
+	return cr.signedHeaders
+}
+
+// MarshalJSON returns the json body of the request, the parameters designated by SetQueryParams are excluded
 func (cr *CommonRequest) MarshalJSON() ([]byte, error) {
-	return json.Marshal(cr.actionParameters)
+	if cr.jsonBody != nil {
+		return cr.jsonBody, nil
+	}
+	if len(cr.queryParams) == 0 {
+		return json.Marshal(cr.actionParameters)
+	}
+	body := make(map[string]interface{}, len(cr.actionParameters))
+	for k, v := range cr.actionParameters {
+		body[k] = v
+	}
+	for _, name := range cr.queryParams {
+		delete(body, name)
+	}
+	return json.Marshal(body)
 }
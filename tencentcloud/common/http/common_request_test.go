@@ -1,9 +1,14 @@
 package common
 
 import (
+	"bytes"
 	"encoding/json"
-	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
 	"testing"
+
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
 )
 
 func TestCommonRequest_SetActionParameters(t *testing.T) {
@@ -138,3 +143,126 @@ func TestCommonRequest_SetOctetStreamParameters(t *testing.T) {
 		}
 	}
 }
+
+func TestCommonRequest_SetMultipart(t *testing.T) {
+	cr := NewCommonRequest("ocr", "2018-11-19", "GeneralBasicOCR")
+	err := cr.SetMultipart([]MultipartPart{
+		{Name: "Scene", Content: []byte("doc")},
+		{Name: "Image", FileName: "a.png", ContentType: "image/png", Content: []byte{0x89, 'P', 'N', 'G'}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if !cr.IsMultipart() || cr.IsOctetStream() {
+		t.Fatalf("expected multipart request")
+	}
+
+	mediaType, params, err := mime.ParseMediaType(cr.GetHeader()["Content-Type"])
+	if err != nil || mediaType != "multipart/form-data" {
+		t.Fatalf("unexpected Content-Type %q", cr.GetHeader()["Content-Type"])
+	}
+	reader := multipart.NewReader(bytes.NewReader(cr.GetMultipartBody()), params["boundary"])
+	expected := []struct{ name, fileName, contentType, content string }{
+		{"Scene", "", "", "doc"},
+		{"Image", "a.png", "image/png", "\x89PNG"},
+	}
+	for _, e := range expected {
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		content, _ := ioutil.ReadAll(part)
+		if part.FormName() != e.name || part.FileName() != e.fileName || part.Header.Get("Content-Type") != e.contentType || string(content) != e.content {
+			t.Fatalf("unexpected part %s %s %v %q", part.FormName(), part.FileName(), part.Header, content)
+		}
+	}
+}
+
+func TestCommonRequest_SetQueryParams(t *testing.T) {
+	request := NewCommonRequest("cvm", "2017-03-12", "DescribeInstances")
+	if err := request.SetActionParameters(`{"Limit": 10, "Name": "foo", "Zone": "ap-guangzhou-3"}`); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	request.SetQueryParams("Limit", "Name", "Missing")
+	params, err := request.GetQueryParams()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if len(params) != 2 || params["Limit"] != "10" || params["Name"] != "foo" {
+		t.Fatalf("unexpected query params %v", params)
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if string(body) != `{"Zone":"ap-guangzhou-3"}` {
+		t.Fatalf("unexpected body %s", body)
+	}
+}
+
+func TestCommonRequest_GetFlatParams(t *testing.T) {
+	request := NewCommonRequest("cvm", "2017-03-12", "DescribeInstances")
+	body := `{"Limit": 10, "Ratio": 0.5, "DryRun": false, "Filters": [{"Name": "zone", "Values": ["a", "b"]}], "Tag": {"Key": "k"}}`
+	if err := request.SetActionParameters(body); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	params, err := request.GetFlatParams()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	expected := map[string]string{
+		"Limit":              "10",
+		"Ratio":              "0.5",
+		"DryRun":             "false",
+		"Filters.0.Name":     "zone",
+		"Filters.0.Values.0": "a",
+		"Filters.0.Values.1": "b",
+		"Tag.Key":            "k",
+	}
+	if len(params) != len(expected) {
+		t.Fatalf("unexpected params %v", params)
+	}
+	for key, value := range expected {
+		if params[key] != value {
+			t.Fatalf("unexpected param %s, expected %s, got %s", key, value, params[key])
+		}
+	}
+}
+
+func TestCommonRequest_Clone(t *testing.T) {
+	request := NewCommonRequest("ccc", "2020-02-10", "DescribeTelCdr")
+	if err := request.SetActionParameters(`{"SdkAppId": 1, "Filters": [{"Name": "Phone", "Values": ["1"]}]}`); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	request.SetHeader(map[string]string{"X-Trace": "1"})
+	CompleteCommonParams(request, "ap-guangzhou")
+
+	clone := request.Clone()
+	CompleteCommonParams(clone, "ap-singapore")
+	clone.GetHeader()["X-Trace"] = "2"
+	clone.actionParameters["Filters"].([]interface{})[0].(map[string]interface{})["Name"] = "Caller"
+	if request.GetParams()["Region"] != "ap-guangzhou" || clone.GetParams()["Region"] != "ap-singapore" {
+		t.Fatalf("params shared with the clone: %v %v", request.GetParams(), clone.GetParams())
+	}
+	if request.GetHeader()["X-Trace"] != "1" {
+		t.Fatalf("header shared with the clone: %v", request.GetHeader())
+	}
+	body, err := json.Marshal(request)
+	if err != nil || string(body) != `{"Filters":[{"Name":"Phone","Values":["1"]}],"SdkAppId":1}` {
+		t.Fatalf("action parameters shared with the clone: %s, %+v", body, err)
+	}
+	if clone.GetAction() != "DescribeTelCdr" || clone.GetService() != "ccc" {
+		t.Fatalf("unexpected clone %s %s", clone.GetService(), clone.GetAction())
+	}
+
+	// the clone gets its own idempotency token
+	request.SetClientToken("token")
+	SetInjectedClientToken(request, "injected")
+	clone = request.Clone()
+	if clone.GetClientToken() != "" || request.GetClientToken() != "token" {
+		t.Fatalf("client token shared with the clone: %s", clone.GetClientToken())
+	}
+	if !IsClonedClientToken(clone, "injected") || IsClonedClientToken(request, "injected") || !IsClonedClientToken(clone.Clone(), "injected") {
+		t.Fatal("injected client token should be recorded as cloned")
+	}
+}
@@ -1,6 +1,9 @@
 package common
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+)
 
 type actionResult map[string]interface{}
 type CommonResponse struct {
@@ -16,8 +19,12 @@ func NewCommonResponse() (response *CommonResponse) {
 	return
 }
 
+// UnmarshalJSON decodes the numbers as json.Number rather than float64,
+// so the large integers, e.g. 64-bit ids, are kept exactly
 func (r *CommonResponse) UnmarshalJSON(data []byte) error {
-	return json.Unmarshal(data, r.actionResult)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(r.actionResult)
 }
 
 func (r *CommonResponse) GetBody() []byte {
@@ -1,11 +1,13 @@
 package common
 
 import (
+	"context"
 	"io"
 	//"log"
 	"math/rand"
 	"net/url"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -33,12 +35,19 @@ type Request interface {
 	GetParams() map[string]string
 	GetPath() string
 	GetService() string
+	GetServiceForDomain() string
 	GetUrl() string
 	GetVersion() string
 	SetScheme(string)
 	SetRootDomain(string)
 	SetDomain(string)
 	SetHttpMethod(string)
+	GetContext() context.Context
+	SetContext(context.Context)
+	GetClientToken() string
+	GetLanguage() (language string, ok bool)
+	GetUnsignedPayload() (unsigned bool, ok bool)
+	GetHeader() map[string]string
 }
 
 type BaseRequest struct {
@@ -53,6 +62,26 @@ type BaseRequest struct {
 	service string
 	version string
 	action  string
+
+	// domainService overrides service when building the domain
+	domainService string
+
+	ctx         context.Context
+	clientToken string
+
+	// injectedClientToken is the token the client injected into the ClientToken field,
+	// clonedClientToken is the one the clone copied from the request it is cloned from
+	injectedClientToken string
+	clonedClientToken   string
+
+	language    string
+	languageSet bool
+
+	unsignedPayload    bool
+	unsignedPayloadSet bool
+
+	// custom header, the reserved ones are ignored
+	header map[string]string
 }
 
 func (r *BaseRequest) GetAction() string {
@@ -131,6 +160,75 @@ func (r *BaseRequest) GetService() string {
 	return r.service
 }
 
+// GetServiceForDomain returns the service name used to build the domain of the request,
+// which is the same as GetService unless it is overridden by SetServiceForDomain
+func (r *BaseRequest) GetServiceForDomain() string {
+	if r.domainService != "" {
+		return r.domainService
+	}
+	return r.service
+}
+
+// SetServiceForDomain overrides the service name used to build the domain of the request,
+// the service name used for signing is not affected. It is useful for services behind gateways.
+func (r *BaseRequest) SetServiceForDomain(service string) {
+	r.domainService = service
+}
+
+// GetClientToken returns the client token specified by SetClientToken
+func (r *BaseRequest) GetClientToken() string {
+	return r.clientToken
+}
+
+// SetClientToken specifies the idempotency token of the request, it is never overwritten
+// by the token injected automatically when retry is enabled, so a deterministic token
+// makes it safe to retry a mutating request, even across process restarts.
+//
+// Note: only the requests which have a ClientToken field support idempotency,
+// e.g. cvm RunInstancesRequest, the token is ignored by other requests.
+func (r *BaseRequest) SetClientToken(token string) {
+	r.clientToken = token
+}
+
+// SetHeader sets the custom headers of the request, e.g. a correlation id for tracing.
+// The headers are not signed, and the reserved headers which the SDK sets itself,
+// e.g. Authorization, Host and X-TC-Action, can not be overridden.
+func (r *BaseRequest) SetHeader(header map[string]string) {
+	if header == nil {
+		return
+	}
+	r.header = header
+}
+
+func (r *BaseRequest) GetHeader() map[string]string {
+	return r.header
+}
+
+// GetLanguage returns the language specified by SetLanguage, ok is false if it is not specified
+func (r *BaseRequest) GetLanguage() (language string, ok bool) {
+	return r.language, r.languageSet
+}
+
+// SetLanguage overrides the Language of the client profile for this request, e.g. zh-CN or en-US,
+// which is the locale of the error messages. An empty language means the language is not sent at all.
+func (r *BaseRequest) SetLanguage(language string) {
+	r.language = language
+	r.languageSet = true
+}
+
+// GetUnsignedPayload returns the choice specified by SetUnsignedPayload, ok is false if it is not specified
+func (r *BaseRequest) GetUnsignedPayload() (unsigned bool, ok bool) {
+	return r.unsignedPayload, r.unsignedPayloadSet
+}
+
+// SetUnsignedPayload overrides the UnsignedPayload of the client profile for this request,
+// an unsigned payload is not hashed when signing, which saves the time for a large body.
+// It only takes effect with the TC3-HMAC-SHA256 sign method.
+func (r *BaseRequest) SetUnsignedPayload(unsigned bool) {
+	r.unsignedPayload = unsigned
+	r.unsignedPayloadSet = true
+}
+
 func (r *BaseRequest) GetUrl() string {
 	if r.httpMethod == GET {
 		return r.GetScheme() + "://" + r.domain + r.path + "?" + GetUrlQueriesEncoded(r.params)
@@ -145,6 +243,27 @@ func (r *BaseRequest) GetVersion() string {
 	return r.version
 }
 
+// SetVersion overrides the API version of the request, e.g. to call another version of the same action
+// during a migration. It is sent in X-TC-Version by signature v3 and in the Version param by signature v1.
+// Note the response is still decoded into the struct of the version the request was generated for.
+func (r *BaseRequest) SetVersion(version string) {
+	r.version = version
+}
+
+// GetContext returns the context of the request, it is never nil
+func (r *BaseRequest) GetContext() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// SetContext sets the context of the request, which controls the cancellation
+// of the request, including waiting for retries and client side rate limiting
+func (r *BaseRequest) SetContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
 func GetUrlQueriesEncoded(params map[string]string) string {
 	values := url.Values{}
 	for key, value := range params {
@@ -153,6 +272,33 @@ func GetUrlQueriesEncoded(params map[string]string) string {
 	return values.Encode()
 }
 
+// GetCanonicalQueryString encodes params as the canonical query string of signature v3,
+// which is sent as the query string of the url as is, so the server side signs exactly the same bytes.
+// The keys are sorted, and everything except the unreserved characters of RFC 3986 is percent encoded,
+// in particular space is encoded as %20 rather than +, which may be decoded ambiguously.
+func GetCanonicalQueryString(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var buf strings.Builder
+	for _, key := range keys {
+		if buf.Len() > 0 {
+			buf.WriteByte('&')
+		}
+		buf.WriteString(escapeRFC3986(key))
+		buf.WriteByte('=')
+		buf.WriteString(escapeRFC3986(params[key]))
+	}
+	return buf.String()
+}
+
+func escapeRFC3986(s string) string {
+	// QueryEscape escapes everything except the unreserved characters, but encodes space as +
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
+
 func (r *BaseRequest) GetBodyReader() io.Reader {
 	if r.httpMethod == POST {
 		s := GetUrlQueriesEncoded(r.params)
@@ -170,6 +316,67 @@ func (r *BaseRequest) Init() *BaseRequest {
 	return r
 }
 
+// Clone returns a copy of the request which shares no params nor header with it,
+// so that a request used as a template can be sent many times, even concurrently,
+// since the common params, e.g. Timestamp, Nonce and Signature, are filled again by every send.
+// The copy of a generated request, e.g. DescribeTelCdrRequest, is made by copying the struct
+// and replacing its BaseRequest with the clone:
+//
+//	clone := *request
+//	clone.BaseRequest = request.BaseRequest.Clone()
+//
+// The clone does not share the idempotency token with the request: the token of SetClientToken
+// is reset, and so is the ClientToken field copied along with the struct once the client injected it,
+// the next send injects a new one. A token assigned to the field by the caller is kept.
+func (r *BaseRequest) Clone() *BaseRequest {
+	clone := *r
+	clone.clientToken = ""
+	clone.injectedClientToken = ""
+	clone.clonedClientToken = r.injectedClientToken
+	if clone.clonedClientToken == "" {
+		// the clone of a clone which is not sent yet
+		clone.clonedClientToken = r.clonedClientToken
+	}
+	clone.params = copyStringMap(r.params)
+	clone.formParams = copyStringMap(r.formParams)
+	clone.header = copyStringMap(r.header)
+	return &clone
+}
+
+func (r *BaseRequest) setInjectedClientToken(token string) {
+	r.injectedClientToken = token
+}
+
+// SetInjectedClientToken records the token injected into the ClientToken field of request if it embeds BaseRequest,
+// it is called by the client, so that the clones of request do not reuse the token, see BaseRequest.Clone.
+func SetInjectedClientToken(request Request, token string) {
+	if r, ok := request.(interface{ setInjectedClientToken(string) }); ok {
+		r.setInjectedClientToken(token)
+	}
+}
+
+func (r *BaseRequest) isClonedClientToken(token string) bool {
+	return token != "" && token == r.clonedClientToken
+}
+
+// IsClonedClientToken reports whether token, the value of the ClientToken field of request,
+// was copied by BaseRequest.Clone from the request it is cloned from, it is called by the client.
+func IsClonedClientToken(request Request, token string) bool {
+	r, ok := request.(interface{ isClonedClientToken(string) bool })
+	return ok && r.isClonedClientToken(token)
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+	return copied
+}
+
 func (r *BaseRequest) WithApiInfo(service, version, action string) *BaseRequest {
 	r.service = service
 	r.version = version
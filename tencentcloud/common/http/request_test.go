@@ -0,0 +1,32 @@
+package common
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestGetCanonicalQueryString(t *testing.T) {
+	params := map[string]string{
+		"Filters.0.Values.0": "a b+c",
+		"Name":               "x&y=z/?#%",
+		"Empty":              "",
+		"Unreserved":         "AZaz09-_.~",
+		"Unicode":            "腾讯*'()!",
+	}
+	expected := "Empty=&Filters.0.Values.0=a%20b%2Bc&Name=x%26y%3Dz%2F%3F%23%25&Unicode=%E8%85%BE%E8%AE%AF%2A%27%28%29%21&Unreserved=AZaz09-_.~"
+	actual := GetCanonicalQueryString(params)
+	if actual != expected {
+		t.Fatalf("unexpected canonical query string\nexpected %s\ngot      %s", expected, actual)
+	}
+
+	// the server decodes the query string sent in the url back to the same params
+	values, err := url.ParseQuery(actual)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	for key, value := range params {
+		if values.Get(key) != value {
+			t.Fatalf("unexpected value of %s, expected %q, got %q", key, value, values.Get(key))
+		}
+	}
+}
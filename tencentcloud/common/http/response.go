@@ -1,20 +1,38 @@
 package common
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	//"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
 )
 
+// HeaderRequestId is the response header which carries the request id,
+// some error responses, e.g. those returned by the gateway, only carry the request id in this header.
+const HeaderRequestId = "X-TC-RequestId"
+
 type Response interface {
 	ParseErrorFromHTTPResponse(body []byte) error
 }
 
 type BaseResponse struct {
+	// Attempts is the number of requests sent by the call if it was retried, it is 0 if no retry happened
+	Attempts int `json:"-"`
+	// RetryReasons is the error codes which caused the retries, e.g. RequestLimitExceeded
+	RetryReasons []string `json:"-"`
+
+	rawBody    []byte
+	requestId  string
+	streamBody io.ReadCloser
+	headers    http.Header
+	timing     *Timing
 }
 
 type ErrorResponse struct {
@@ -33,6 +51,235 @@ type DeprecatedAPIErrorResponse struct {
 	CodeDesc string `json:"codeDesc"`
 }
 
+// GetRawBody returns the raw json body of the response, it is nil unless the client keeps it, see Client.WithRawBody.
+// The raw body shares the buffer which the response is decoded from, so no extra copy is made.
+func (r *BaseResponse) GetRawBody() []byte {
+	return r.rawBody
+}
+
+func (r *BaseResponse) setRawBody(body []byte) {
+	r.rawBody = body
+}
+
+// SetRawBody sets the raw body returned by GetRawBody if response embeds BaseResponse,
+// e.g. the client clears it after the call unless it is kept.
+func SetRawBody(response Response, body []byte) {
+	if r, ok := response.(interface{ setRawBody([]byte) }); ok {
+		r.setRawBody(body)
+	}
+}
+
+// GetStreamBody returns the body of the response if the server responds with application/octet-stream,
+// e.g. a binary download, the body is not buffered and must be closed by the caller after reading.
+// Note the ReqTimeout of HttpProfile covers reading the body as well. It is nil for the json responses.
+func (r *BaseResponse) GetStreamBody() io.ReadCloser {
+	return r.streamBody
+}
+
+func (r *BaseResponse) setStreamBody(body io.ReadCloser) {
+	r.streamBody = body
+}
+
+// StreamResponse is a response whose json body is not buffered nor parsed by the client,
+// the body is handed over to the caller by GetStreamBody to be decoded incrementally, e.g. with json.Decoder.Token,
+// and must be closed by the caller. Since the body is not read by the client, the API error in it is not detected,
+// nor is the request retried on it, the caller should check the Response.Error of the body.
+type StreamResponse struct {
+	*BaseResponse
+}
+
+func NewStreamResponse() *StreamResponse {
+	return &StreamResponse{BaseResponse: &BaseResponse{}}
+}
+
+func (r *StreamResponse) isStreamResponse() bool {
+	return true
+}
+
+// IsStreamBody reports whether the body of hr is handed over to response without buffering,
+// i.e. a binary body, or a successful json body of a StreamResponse
+func IsStreamBody(hr *http.Response, response Response) bool {
+	if IsOctetStreamResponse(hr) {
+		return true
+	}
+	_, ok := response.(interface{ isStreamResponse() bool })
+	return ok && hr.StatusCode == http.StatusOK
+}
+
+// ToJSONString marshals response again into json for logging, e.g. a generated response whose nil fields
+// are omitted, or a CommonResponse. The fields of BaseResponse, e.g. the headers and the raw body, are excluded.
+// The body of a binary response or a StreamResponse is not included since it is never buffered.
+func ToJSONString(response Response) (string, error) {
+	if cr, ok := response.(*CommonResponse); ok {
+		return string(cr.GetBody()), nil
+	}
+	b, err := json.Marshal(response)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// IsOctetStreamResponse reports whether the server responds with a binary body rather than json
+func IsOctetStreamResponse(hr *http.Response) bool {
+	return hr.StatusCode == http.StatusOK && strings.HasPrefix(hr.Header.Get("Content-Type"), octetStream)
+}
+
+// GetHeaders returns the http headers of the response, e.g. X-TC-RequestId or the rate limit counters,
+// they are captured for the error responses as well. It is nil before the response is received.
+func (r *BaseResponse) GetHeaders() http.Header {
+	return r.headers
+}
+
+func (r *BaseResponse) setHeaders(header http.Header) {
+	r.headers = header
+}
+
+// SetHeaders sets the headers returned by GetHeaders if response embeds BaseResponse,
+// it is called by the client for each response received, including the error ones.
+func SetHeaders(response Response, header http.Header) {
+	if r, ok := response.(interface{ setHeaders(http.Header) }); ok {
+		r.setHeaders(header)
+	}
+}
+
+func (r *BaseResponse) setRetryInfo(attempts int, reasons []string) {
+	r.Attempts = attempts
+	r.RetryReasons = reasons
+}
+
+// SetRetryInfo fills Attempts and RetryReasons of response if it embeds BaseResponse,
+// it is called by the client after the retries of a call.
+func SetRetryInfo(response Response, attempts int, reasons []string) {
+	if r, ok := response.(interface{ setRetryInfo(int, []string) }); ok {
+		r.setRetryInfo(attempts, reasons)
+	}
+}
+
+// Timing is the timing of a call captured by Client.WithTimingCapture. The phases of the connection
+// are those of the last attempt, they are zero if the attempt reused an idle connection.
+type Timing struct {
+	// Signing is the time to build and sign the request before sending it
+	Signing time.Duration
+	// DNSLookup is the time to resolve the domain of the request
+	DNSLookup time.Duration
+	// Connect is the time to establish the TCP connection
+	Connect time.Duration
+	// TLSHandshake is the time of the TLS handshake after the connection is established
+	TLSHandshake time.Duration
+	// TimeToFirstByte is the time from sending the last attempt to receiving the first byte of its response,
+	// including the phases of the connection above, i.e. the time taken by the network and the server
+	TimeToFirstByte time.Duration
+	// ReusedConnection reports whether the last attempt was sent through an idle connection
+	ReusedConnection bool
+	// Total is the time from signing to receiving the response, including the retries and the sleeps between them
+	Total time.Duration
+}
+
+// GetTiming returns the timing of the call, it is nil unless the client enables Client.WithTimingCapture.
+func (r *BaseResponse) GetTiming() *Timing {
+	return r.timing
+}
+
+func (r *BaseResponse) setTiming(timing *Timing) {
+	r.timing = timing
+}
+
+// SetTiming sets the timing returned by GetTiming if response embeds BaseResponse,
+// it is called by the client after the call if the timing is captured.
+func SetTiming(response Response, timing *Timing) {
+	if r, ok := response.(interface{ setTiming(*Timing) }); ok {
+		r.setTiming(timing)
+	}
+}
+
+// GetRequestId returns the id of the request which produced the response.
+// The id in the body envelope is preferred, the X-TC-RequestId header is used when the body has none.
+func (r *BaseResponse) GetRequestId() string {
+	return r.requestId
+}
+
+func (r *BaseResponse) setRequestId(requestId string) {
+	r.requestId = requestId
+}
+
+// GetRequestIdFromHeader returns the request id carried by the X-TC-RequestId header
+func GetRequestIdFromHeader(header http.Header) string {
+	if v := header.Get(HeaderRequestId); v != "" {
+		return v
+	}
+	// the header may be set with a non canonical key
+	if v := header[HeaderRequestId]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// FillRequestId sets requestId to err when err is a TencentCloudSDKError without request id
+func FillRequestId(err error, requestId string) error {
+	if sdkErr, ok := err.(*errors.TencentCloudSDKError); ok && sdkErr.RequestId == "" {
+		sdkErr.RequestId = requestId
+	}
+	return err
+}
+
+func getRequestIdFromBody(body []byte) string {
+	envelope := struct {
+		Response struct {
+			RequestId string `json:"RequestId"`
+		} `json:"Response"`
+	}{}
+	// the body may be malformed, the error is reported by the parsing below
+	_ = json.Unmarshal(body, &envelope)
+	return envelope.Response.RequestId
+}
+
+// limitedBody fails the reading with ClientError.ResponseBodyTooLarge once more than limit bytes are read
+type limitedBody struct {
+	io.Reader
+	closer io.Closer
+	limit  int64
+	read   int64
+}
+
+// LimitBody returns body which fails the reading with the error code ClientError.ResponseBodyTooLarge
+// once more than limit bytes are read, so a gigantic body, e.g. returned by a broken proxy, is not buffered.
+func LimitBody(body io.ReadCloser, limit int64) io.ReadCloser {
+	return &limitedBody{Reader: io.LimitReader(body, limit+1), closer: body, limit: limit}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	b.read += int64(n)
+	if b.read > b.limit {
+		msg := fmt.Sprintf("Response body is larger than the limit %d bytes", b.limit)
+		return 0, errors.NewTencentCloudSDKError("ClientError.ResponseBodyTooLarge", msg, "")
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.closer.Close()
+}
+
+// maxBodySnippet is the max length of the body quoted by the error of a malformed response
+const maxBodySnippet = 256
+
+// CheckRequestId returns an error if body has no Response.RequestId, which every response of the API has,
+// so the body is probably returned by a proxy or a captive portal, e.g. a login page with the status 200.
+// The error quotes the first bytes of the body for diagnosis.
+func CheckRequestId(body []byte) error {
+	if getRequestIdFromBody(body) != "" {
+		return nil
+	}
+	snippet := body
+	if len(snippet) > maxBodySnippet {
+		snippet = snippet[:maxBodySnippet]
+	}
+	msg := fmt.Sprintf("Response.RequestId is missing, the response may not come from the API, body: %q", snippet)
+	return errors.NewTencentCloudSDKError("ClientError.MissingRequestId", msg, "")
+}
+
 func (r *BaseResponse) ParseErrorFromHTTPResponse(body []byte) (err error) {
 	resp := &ErrorResponse{}
 	err = json.Unmarshal(body, resp)
@@ -41,7 +288,7 @@ func (r *BaseResponse) ParseErrorFromHTTPResponse(body []byte) (err error) {
 		return errors.NewTencentCloudSDKError("ClientError.ParseJsonError", msg, "")
 	}
 	if resp.Response.Error.Code != "" {
-		return errors.NewTencentCloudSDKError(resp.Response.Error.Code, resp.Response.Error.Message, resp.Response.RequestId)
+		return newAPIError(resp, body)
 	}
 
 	deprecated := &DeprecatedAPIErrorResponse{}
@@ -56,6 +303,18 @@ func (r *BaseResponse) ParseErrorFromHTTPResponse(body []byte) (err error) {
 	return nil
 }
 
+// newAPIError returns the error of resp, which carries the raw Response.Error object of body as the detail
+func newAPIError(resp *ErrorResponse, body []byte) error {
+	raw := struct {
+		Response struct {
+			Error json.RawMessage `json:"Error"`
+		} `json:"Response"`
+	}{}
+	// body is already parsed successfully
+	_ = json.Unmarshal(body, &raw)
+	return errors.NewTencentCloudSDKErrorWithDetail(resp.Response.Error.Code, resp.Response.Error.Message, resp.Response.RequestId, raw.Response.Error)
+}
+
 func ParseErrorFromHTTPResponse(body []byte) (err error) {
 	resp := &ErrorResponse{}
 	err = json.Unmarshal(body, resp)
@@ -64,7 +323,7 @@ func ParseErrorFromHTTPResponse(body []byte) (err error) {
 		return errors.NewTencentCloudSDKError("ClientError.ParseJsonError", msg, "")
 	}
 	if resp.Response.Error.Code != "" {
-		return errors.NewTencentCloudSDKError(resp.Response.Error.Code, resp.Response.Error.Message, resp.Response.RequestId)
+		return newAPIError(resp, body)
 	}
 
 	deprecated := &DeprecatedAPIErrorResponse{}
@@ -80,25 +339,53 @@ func ParseErrorFromHTTPResponse(body []byte) (err error) {
 }
 
 func ParseFromHttpResponse(hr *http.Response, response Response) (err error) {
+	requestId := GetRequestIdFromHeader(hr.Header)
+	SetHeaders(response, hr.Header)
+	if IsStreamBody(hr, response) {
+		// the body is handed over to the caller, so it must not be closed here
+		if br, ok := response.(interface{ setStreamBody(io.ReadCloser) }); ok {
+			br.setStreamBody(hr.Body)
+			if br, ok := response.(interface{ setRequestId(string) }); ok {
+				br.setRequestId(requestId)
+			}
+			return nil
+		}
+	}
 	defer hr.Body.Close()
 	body, err := ioutil.ReadAll(hr.Body)
 	if err != nil {
+		if _, ok := err.(*errors.TencentCloudSDKError); ok {
+			return FillRequestId(err, requestId)
+		}
 		msg := fmt.Sprintf("Fail to read response body because %s", err)
-		return errors.NewTencentCloudSDKError("ClientError.IOError", msg, "")
+		return errors.NewTencentCloudSDKErrorWithCause("ClientError.IOError", msg, requestId, err)
+	}
+	SetRawBody(response, body)
+	if id := getRequestIdFromBody(body); id != "" {
+		requestId = id
+	}
+	if br, ok := response.(interface{ setRequestId(string) }); ok {
+		br.setRequestId(requestId)
 	}
 	if hr.StatusCode != 200 {
 		msg := fmt.Sprintf("Request fail with http status code: %s, with body: %s", hr.Status, body)
-		return errors.NewTencentCloudSDKError("ClientError.HttpStatusCodeError", msg, "")
+		return errors.NewTencentCloudSDKError("ClientError.HttpStatusCodeError", msg, requestId)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		// the API never returns an empty body, it is probably dropped by a proxy or a load balancer
+		msg := fmt.Sprintf("Response body is empty with http status code: %d, "+
+			"it may be returned by a proxy or a load balancer instead of the API", hr.StatusCode)
+		return errors.NewTencentCloudSDKError("ClientError.EmptyResponseBody", msg, requestId)
 	}
 	//log.Printf("[DEBUG] Response Body=%s", body)
 	err = response.ParseErrorFromHTTPResponse(body)
 	if err != nil {
-		return
+		return FillRequestId(err, requestId)
 	}
 	err = json.Unmarshal(body, &response)
 	if err != nil {
 		msg := fmt.Sprintf("Fail to parse json content: %s, because: %s", body, err)
-		return errors.NewTencentCloudSDKError("ClientError.ParseJsonError", msg, "")
+		return errors.NewTencentCloudSDKError("ClientError.ParseJsonError", msg, requestId)
 	}
 	return
 }
@@ -0,0 +1,274 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+func newHttpResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Status:     http.StatusText(statusCode),
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+	}
+}
+
+func TestParseFromHttpResponse_RawBody(t *testing.T) {
+	body := `{"Response": {"RequestId": "req-1", "Total": 1}}`
+	response := NewCommonResponse()
+	if err := ParseFromHttpResponse(newHttpResponse(200, body), response); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if string(response.GetRawBody()) != body {
+		t.Fatalf("unexpected raw body, expected %s, got %s", body, response.GetRawBody())
+	}
+}
+
+func TestParseFromHttpResponse_RequestId(t *testing.T) {
+	cases := []struct {
+		name      string
+		header    string
+		body      string
+		requestId string
+	}{
+		{"header only", "req-header", `{"Response": {"Total": 1}}`, "req-header"},
+		{"body only", "", `{"Response": {"RequestId": "req-body", "Total": 1}}`, "req-body"},
+		{"both", "req-header", `{"Response": {"RequestId": "req-body", "Total": 1}}`, "req-body"},
+	}
+	for _, c := range cases {
+		hr := newHttpResponse(200, c.body)
+		if c.header != "" {
+			hr.Header.Set(HeaderRequestId, c.header)
+		}
+		response := NewCommonResponse()
+		if err := ParseFromHttpResponse(hr, response); err != nil {
+			t.Fatalf("%s: unexpected error: %+v", c.name, err)
+		}
+		if response.GetRequestId() != c.requestId {
+			t.Fatalf("%s: unexpected request id, expected %s, got %s", c.name, c.requestId, response.GetRequestId())
+		}
+	}
+}
+
+func TestParseFromHttpResponse_ErrorRequestId(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		header     string
+		body       string
+		requestId  string
+	}{
+		{"header only", 502, "req-header", `Bad Gateway`, "req-header"},
+		{"body only", 200, "", `{"Response": {"Error": {"Code": "AuthFailure", "Message": "m"}, "RequestId": "req-body"}}`, "req-body"},
+		{"both", 200, "req-header", `{"Response": {"Error": {"Code": "AuthFailure", "Message": "m"}, "RequestId": "req-body"}}`, "req-body"},
+		{"error without body id", 200, "req-header", `{"Response": {"Error": {"Code": "AuthFailure", "Message": "m"}}}`, "req-header"},
+	}
+	for _, c := range cases {
+		hr := newHttpResponse(c.statusCode, c.body)
+		if c.header != "" {
+			hr.Header.Set(HeaderRequestId, c.header)
+		}
+		response := NewCommonResponse()
+		err := ParseFromHttpResponse(hr, response)
+		sdkErr, ok := err.(*errors.TencentCloudSDKError)
+		if !ok {
+			t.Fatalf("%s: unexpected error: %+v", c.name, err)
+		}
+		if sdkErr.GetRequestId() != c.requestId || response.GetRequestId() != c.requestId {
+			t.Fatalf("%s: unexpected request id, expected %s, got %s and %s", c.name, c.requestId, sdkErr.GetRequestId(), response.GetRequestId())
+		}
+	}
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestParseFromHttpResponse_OctetStream(t *testing.T) {
+	body := &closeRecorder{Reader: bytes.NewBufferString("\x00\x01binary")}
+	hr := &http.Response{StatusCode: 200, Header: http.Header{}, Body: body}
+	hr.Header.Set("Content-Type", "application/octet-stream")
+	hr.Header.Set(HeaderRequestId, "req-1")
+
+	response := NewCommonResponse()
+	if err := ParseFromHttpResponse(hr, response); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if body.closed {
+		t.Fatalf("stream body should not be closed before it is read")
+	}
+	b, err := ioutil.ReadAll(response.GetStreamBody())
+	if err != nil || string(b) != "\x00\x01binary" {
+		t.Fatalf("unexpected stream body %q, %+v", b, err)
+	}
+	if response.GetRequestId() != "req-1" || response.GetRawBody() != nil {
+		t.Fatalf("unexpected response %s %s", response.GetRequestId(), response.GetRawBody())
+	}
+}
+
+func TestParseFromHttpResponse_LargeInteger(t *testing.T) {
+	// 2^53 + 1 can not be represented by float64
+	body := `{"Response": {"RequestId": "req-1", "CdrId": 9007199254740993, "SessionId": 18446744073709551615}}`
+
+	response := NewCommonResponse()
+	if err := ParseFromHttpResponse(newHttpResponse(200, body), response); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	expected := `{"Response":{"CdrId":9007199254740993,"RequestId":"req-1","SessionId":18446744073709551615}}`
+	if string(response.GetBody()) != expected {
+		t.Fatalf("precision lost, expected %s, got %s", expected, response.GetBody())
+	}
+
+	typed := &struct {
+		*BaseResponse
+		Response *struct {
+			CdrId     *int64  `json:"CdrId"`
+			SessionId *uint64 `json:"SessionId"`
+		} `json:"Response"`
+	}{BaseResponse: &BaseResponse{}}
+	if err := ParseFromHttpResponse(newHttpResponse(200, body), typed); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if *typed.Response.CdrId != 9007199254740993 || *typed.Response.SessionId != 18446744073709551615 {
+		t.Fatalf("precision lost, got %d %d", *typed.Response.CdrId, *typed.Response.SessionId)
+	}
+}
+
+func TestCheckRequestId(t *testing.T) {
+	if err := CheckRequestId([]byte(`{"Response": {"RequestId": "req-1"}}`)); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	page := "<html><body>Please login" + strings.Repeat(".", 1024) + "</body></html>"
+	for body, quoted := range map[string]string{`{"Response": {}}`: `Response`, page: "Please login"} {
+		err := CheckRequestId([]byte(body))
+		sdkErr, ok := err.(*errors.TencentCloudSDKError)
+		if !ok || sdkErr.GetCode() != "ClientError.MissingRequestId" {
+			t.Fatalf("expected missing request id error, got %+v", err)
+		}
+		// only the first bytes of the body are quoted
+		if !strings.Contains(sdkErr.GetMessage(), quoted) || len(sdkErr.GetMessage()) > 512 {
+			t.Fatalf("unexpected message %s", sdkErr.GetMessage())
+		}
+	}
+}
+
+func TestParseErrorDetail(t *testing.T) {
+	body := `{"Response": {"RequestId": "req-1", "Error": {"Code": "LimitExceeded", "Message": "quota exceeded", "SubCode": "SeatQuota", "Quota": 10}}}`
+	err := ParseFromHttpResponse(newHttpResponse(200, body), NewCommonResponse())
+	sdkErr, ok := err.(*errors.TencentCloudSDKError)
+	if !ok || sdkErr.GetCode() != "LimitExceeded" || sdkErr.GetMessage() != "quota exceeded" {
+		t.Fatalf("unexpected error %+v", err)
+	}
+	detail := struct {
+		SubCode string
+		Quota   int
+	}{}
+	if err := json.Unmarshal(sdkErr.Detail(), &detail); err != nil || detail.SubCode != "SeatQuota" || detail.Quota != 10 {
+		t.Fatalf("unexpected detail %s, %+v", sdkErr.Detail(), err)
+	}
+}
+
+func TestParseFromHttpResponse_Headers(t *testing.T) {
+	for _, c := range []struct {
+		statusCode int
+		body       string
+	}{
+		{200, `{"Response": {"RequestId": "req-1"}}`},
+		{200, `{"Response": {"RequestId": "req-1", "Error": {"Code": "RequestLimitExceeded"}}}`},
+		{502, `bad gateway`},
+	} {
+		hr := newHttpResponse(c.statusCode, c.body)
+		hr.Header.Set("X-RateLimit-Remaining", "9")
+		response := NewCommonResponse()
+		_ = ParseFromHttpResponse(hr, response)
+		if v := response.GetHeaders().Get("X-RateLimit-Remaining"); v != "9" {
+			t.Fatalf("unexpected header of %s, got %q", c.body, v)
+		}
+	}
+}
+
+func TestParseFromHttpResponse_StreamResponse(t *testing.T) {
+	body := &closeRecorder{Reader: bytes.NewBufferString(`{"Response": {"RequestId": "req-1", "Items": [1, 2]}}`)}
+	hr := &http.Response{StatusCode: 200, Header: http.Header{}, Body: body}
+
+	response := NewStreamResponse()
+	if err := ParseFromHttpResponse(hr, response); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if body.closed || response.GetStreamBody() == nil || response.GetRawBody() != nil {
+		t.Fatalf("json body of stream response should be handed over without buffering")
+	}
+
+	hr = newHttpResponse(502, "bad gateway")
+	if err := ParseFromHttpResponse(hr, NewStreamResponse()); err == nil {
+		t.Fatalf("expected error for non 200 status")
+	}
+}
+
+func TestParseFromHttpResponse_EmptyBody(t *testing.T) {
+	for _, body := range []string{"", " \r\n\t"} {
+		hr := newHttpResponse(200, body)
+		hr.Header.Set(HeaderRequestId, "req-1")
+		err := ParseFromHttpResponse(hr, NewCommonResponse())
+		sdkErr, ok := err.(*errors.TencentCloudSDKError)
+		if !ok || sdkErr.GetCode() != "ClientError.EmptyResponseBody" || sdkErr.GetRequestId() != "req-1" {
+			t.Fatalf("unexpected error %+v", err)
+		}
+		if !strings.Contains(sdkErr.GetMessage(), "200") {
+			t.Fatalf("status code is missing in message %s", sdkErr.GetMessage())
+		}
+	}
+}
+
+func TestLimitBody(t *testing.T) {
+	body := `{"Response": {"RequestId": "req-1"}}`
+	if err := ParseFromHttpResponse(&http.Response{StatusCode: 200, Header: http.Header{}, Body: LimitBody(ioutil.NopCloser(strings.NewReader(body)), int64(len(body)))}, NewCommonResponse()); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	err := ParseFromHttpResponse(&http.Response{StatusCode: 200, Header: http.Header{}, Body: LimitBody(ioutil.NopCloser(strings.NewReader(body)), 10)}, NewCommonResponse())
+	if sdkErr, ok := err.(*errors.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.ResponseBodyTooLarge" {
+		t.Fatalf("unexpected error %+v", err)
+	}
+}
+
+func TestToJSONString(t *testing.T) {
+	body := `{"Response": {"RequestId": "req-1", "Total": 2, "Items": [{"Id": 1}]}}`
+	typed := &struct {
+		*BaseResponse
+		Response *struct {
+			Total     *int64  `json:"Total,omitempty"`
+			Name      *string `json:"Name,omitempty"`
+			RequestId *string `json:"RequestId,omitempty"`
+		} `json:"Response"`
+	}{BaseResponse: &BaseResponse{Attempts: 2, RetryReasons: []string{"RequestLimitExceeded"}}}
+	if err := ParseFromHttpResponse(newHttpResponse(200, body), typed); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	s, err := ToJSONString(typed)
+	if err != nil || s != `{"Response":{"Total":2,"RequestId":"req-1"}}` {
+		t.Fatalf("unexpected json %s, %+v", s, err)
+	}
+
+	common := NewCommonResponse()
+	if err := ParseFromHttpResponse(newHttpResponse(200, body), common); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	s, err = ToJSONString(common)
+	if err != nil || s != `{"Response":{"Items":[{"Id":1}],"RequestId":"req-1","Total":2}}` {
+		t.Fatalf("unexpected json %s, %+v", s, err)
+	}
+}
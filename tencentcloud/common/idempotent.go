@@ -0,0 +1,68 @@
+package common
+
+import (
+	"log"
+	"sync"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+const (
+	tplNonIdempotentRetry = "[WARN] retrying non-idempotent action %s without a client token, it may take effect more than once: %s"
+)
+
+var (
+	idempotentActionsMu sync.RWMutex
+	// idempotentActions are keyed by service, version and action, e.g. ccc|2020-02-10|DeleteStaff
+	idempotentActions = map[string]bool{}
+)
+
+// RegisterIdempotentActions marks the actions of service and version which take effect once however many times
+// they are sent, e.g. DescribeXxx or DeleteXxx, so that they are retried without a ClientToken field,
+// see ClientProfile.RetryWithoutClientToken.
+// It is called by the service packages from their metadata.
+func RegisterIdempotentActions(service, version string, actions ...string) {
+	idempotentActionsMu.Lock()
+	defer idempotentActionsMu.Unlock()
+	for _, action := range actions {
+		idempotentActions[service+"|"+version+"|"+action] = true
+	}
+}
+
+// IsIdempotentAction reports whether action of service and version is safe to send more than once,
+// i.e. it is a read action accepted by IsReadAction or it is registered by RegisterIdempotentActions
+func IsIdempotentAction(service, version, action string) bool {
+	return IsReadAction(action) || isRegisteredIdempotentAction(service, version, action)
+}
+
+func isRegisteredIdempotentAction(service, version, action string) bool {
+	idempotentActionsMu.RLock()
+	defer idempotentActionsMu.RUnlock()
+	return idempotentActions[service+"|"+version+"|"+action]
+}
+
+// retryPolicy tells whether the network failures, the 5xx responses and RetryableErrorCodes are retried
+// for request. A request is retryable if it has a ClientToken field, which is kept by the retries,
+// or its action is registered by RegisterIdempotentActions, the other requests are retried only if
+// RetryWithoutClientToken is set. The returned action is not empty if the request is retried
+// but its action is not idempotent, so that its retries are warned.
+func (c *Client) retryPolicy(request tchttp.Request) (retryable bool, unsafeAction string) {
+	if isRetryable(request) || isRegisteredIdempotentAction(request.GetService(), request.GetVersion(), request.GetAction()) {
+		return true, ""
+	}
+	if !c.profile.RetryWithoutClientToken {
+		return false, ""
+	}
+	if IsIdempotentAction(request.GetService(), request.GetVersion(), request.GetAction()) {
+		return true, ""
+	}
+	return true, request.GetAction()
+}
+
+// warnNonIdempotentRetry logs the retry of a non-idempotent action, RequestLimitExceeded is not warned
+// since the rate limited request never takes effect
+func warnNonIdempotentRetry(action, reason string) {
+	if action != "" && reason != codeLimitExceeded {
+		log.Printf(tplNonIdempotentRetry, action, reason)
+	}
+}
@@ -49,7 +49,7 @@ func parse(path string) (*sections, error) {
 		// section name
 		if strings.HasPrefix(line, "[") {
 			if strings.HasSuffix(line, "]") {
-				tempSection := line[1 : len(line)-1]
+				tempSection := strings.TrimSpace(line[1 : len(line)-1])
 				if len(tempSection) == 0 {
 					msg := fmt.Sprintf("INI file %s lien %d is not valid: wrong section", path, i)
 					return result, tcerr.NewTencentCloudSDKError(iniErr, msg, "")
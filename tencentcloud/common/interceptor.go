@@ -0,0 +1,22 @@
+package common
+
+import (
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// ResponseInterceptor is invoked with the action name and the decoded response of every successful call
+type ResponseInterceptor func(action string, resp tchttp.Response)
+
+// WithResponseInterceptor registers fn which is invoked after the response is decoded successfully,
+// e.g. to normalize fields or to write audit logs. It is not invoked when Send returns an error,
+// and it can not change the error returned by Send.
+func (c *Client) WithResponseInterceptor(fn ResponseInterceptor) *Client {
+	c.interceptor = fn
+	return c
+}
+
+func (c *Client) interceptResponse(action string, response tchttp.Response) {
+	if c.interceptor != nil {
+		c.interceptor(action, response)
+	}
+}
@@ -3,12 +3,11 @@ package common
 import (
 	"fmt"
 	"log"
-	"net"
 	"net/http"
 	"reflect"
-	"time"
 
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
 )
 
@@ -16,34 +15,50 @@ const (
 	tplNetworkFailureRetry = "[WARN] temporary network failure, retrying (%d/%d) in %f seconds: %s"
 )
 
-func (c *Client) sendWithNetworkFailureRetry(req *http.Request, retryable bool) (resp *http.Response, err error) {
+func (c *Client) sendWithNetworkFailureRetry(req *http.Request, request tchttp.Request, retryable bool, stats *retryStats) (resp *http.Response, err error) {
 	// make sure maxRetries is more than or equal 0
 	var maxRetries int
-	if retryable {
+	if retryable || c.retryDecider != nil {
 		maxRetries = maxInt(c.profile.NetworkFailureMaxRetries, 0)
 	}
 	durationFunc := safeDurationFunc(c.profile.NetworkFailureRetryDuration)
 
 	for idx := 0; idx <= maxRetries; idx++ {
-		resp, err = c.sendHttp(req)
+		if c.rateLimiter != nil {
+			if err = c.rateLimiter.Wait(req.Context()); err != nil {
+				msg := fmt.Sprintf("Fail to wait for rate limiter because %s", err)
+				return nil, errors.NewTencentCloudSDKErrorWithCause("ClientError.RateLimiterError", msg, "", err)
+			}
+		}
+
+		resp, err = c.sendHttp(stats.trace.trace(req))
+		stats.attempts++
 
 		// retry when error occurred and retryable and not the last retry
 		// should not sleep on last retry even if it's retryable
-		if err != nil && retryable && idx < maxRetries {
-			if err, ok := err.(net.Error); ok && (err.Timeout() || err.Temporary()) {
+		exhausted := false
+		// the context error is never retried since the deadline is shared by all the retries
+		if err != nil && maxRetries > 0 && req.Context().Err() == nil {
+			if c.retryNetworkError(request, err) {
 				duration := durationFunc(idx)
-				if c.debug {
-					log.Printf(tplNetworkFailureRetry, idx, maxRetries, duration.Seconds(), err.Error())
-				}
+				if idx < maxRetries && c.withinRetryElapsedTime(stats, duration) && c.acquireRetry(err) {
+					if c.debug {
+						log.Printf(tplNetworkFailureRetry, idx, maxRetries, duration.Seconds(), err.Error())
+					}
 
-				time.Sleep(duration)
-				continue
+					stats.retry(req.Context(), "ClientError.NetworkError", err, duration)
+					continue
+				}
+				exhausted = true
 			}
 		}
 
 		if err != nil {
 			msg := fmt.Sprintf("Fail to get response because %s", err)
-			err = errors.NewTencentCloudSDKError("ClientError.NetworkError", msg, "")
+			err = errors.NewTencentCloudSDKErrorWithCause("ClientError.NetworkError", msg, "", err)
+			if exhausted {
+				c.onRetryExhausted(stats, err)
+			}
 		}
 
 		return resp, err
@@ -0,0 +1,148 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+const (
+	// PaginationValidationNone does not validate the item counts
+	PaginationValidationNone = iota
+	// PaginationValidationWarn logs a warning when the item counts are inconsistent
+	PaginationValidationWarn
+	// PaginationValidationError stops with an *ErrPaginationInconsistent when the item counts are inconsistent
+	PaginationValidationError
+
+	tplPaginationInconsistent = "[WARN] %s"
+)
+
+// PageFunc fetches the page which starts at offset and contains at most limit items,
+// it returns the number of items in the page and the total count reported by the API.
+type PageFunc func(ctx context.Context, offset, limit int64) (count, total int64, err error)
+
+// ErrPaginationInconsistent means the items fetched page by page diverge from the total count
+// reported by the API, usually because the resources are mutated on the server side concurrently,
+// so some records may be missed or duplicated.
+type ErrPaginationInconsistent struct {
+	// Expected is the total count reported by the API
+	Expected int64
+	// Observed is the number of items actually fetched,
+	// or the total count reported by a later page if it changed during pagination
+	Observed int64
+}
+
+func (e *ErrPaginationInconsistent) Error() string {
+	return fmt.Sprintf("pagination inconsistent: expected %d items in total, observed %d", e.Expected, e.Observed)
+}
+
+// Paginator walks through an offset/limit style API page by page
+type Paginator struct {
+	limit      int64
+	validation int
+	fetch      PageFunc
+}
+
+// NewPaginator returns a paginator which fetches limit items per page with fetch
+func NewPaginator(limit int64, fetch PageFunc) *Paginator {
+	return &Paginator{
+		limit:      limit,
+		validation: PaginationValidationNone,
+		fetch:      fetch,
+	}
+}
+
+// WithValidation sets how the paginator validates the fetched items against the total count,
+// valid choices: PaginationValidationNone, PaginationValidationWarn, PaginationValidationError
+func (p *Paginator) WithValidation(validation int) *Paginator {
+	p.validation = validation
+	return p
+}
+
+// Run fetches all the pages until the total count is reached or an empty page is returned
+func (p *Paginator) Run(ctx context.Context) error {
+	var offset, expected int64 = 0, -1
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		count, total, err := p.fetch(ctx, offset, p.limit)
+		if err != nil {
+			return err
+		}
+		// the total count changed between pages
+		if expected >= 0 && total != expected {
+			if err := p.inconsistent(expected, total); err != nil {
+				return err
+			}
+		}
+		expected = total
+		offset += count
+		if count == 0 || offset >= total {
+			break
+		}
+	}
+	if offset != expected {
+		return p.inconsistent(expected, offset)
+	}
+	return nil
+}
+
+func (p *Paginator) inconsistent(expected, observed int64) error {
+	err := &ErrPaginationInconsistent{Expected: expected, Observed: observed}
+	switch p.validation {
+	case PaginationValidationWarn:
+		log.Printf(tplPaginationInconsistent, err.Error())
+	case PaginationValidationError:
+		return err
+	}
+	return nil
+}
+
+// TokenPageFunc fetches the page which starts at token, the token is empty for the first page,
+// it returns the token of the next page reported by the API, e.g. the NextToken of the response,
+// which is empty for the last page. It sets the token on the request of any API and reads it
+// from the response, e.g.
+//
+//	func(ctx context.Context, token string) (string, error) {
+//		if token != "" {
+//			request.NextToken = common.StringPtr(token)
+//		}
+//		request.SetContext(ctx)
+//		response, err := client.DescribeXxx(request)
+//		if err != nil {
+//			return "", err
+//		}
+//		// handle response.Response.Items
+//		if response.Response.NextToken == nil {
+//			return "", nil
+//		}
+//		return *response.Response.NextToken, nil
+//	}
+type TokenPageFunc func(ctx context.Context, token string) (nextToken string, err error)
+
+// IterateTokens walks through a NextToken style API page by page with fetch until the token of
+// the next page is empty, it complements Paginator for the APIs which report no total count.
+// A token returned twice in a row fails with ClientError.PaginationTokenRepeated, rather than loops forever.
+func IterateTokens(ctx context.Context, fetch TokenPageFunc) error {
+	token := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		next, err := fetch(ctx, token)
+		if err != nil {
+			return err
+		}
+		if next == "" {
+			return nil
+		}
+		if next == token {
+			msg := fmt.Sprintf("pagination token %q is returned twice in a row", next)
+			return tcerr.NewTencentCloudSDKError("ClientError.PaginationTokenRepeated", msg, "")
+		}
+		token = next
+	}
+}
@@ -0,0 +1,86 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+func pages(totals ...int64) PageFunc {
+	call := 0
+	return func(ctx context.Context, offset, limit int64) (int64, int64, error) {
+		total := totals[call]
+		if call < len(totals)-1 {
+			call++
+		}
+		count := total - offset
+		if count > limit {
+			count = limit
+		}
+		if count < 0 {
+			count = 0
+		}
+		return count, total, nil
+	}
+}
+
+func TestPaginatorConsistent(t *testing.T) {
+	var fetched int64
+	fetch := pages(25)
+	err := NewPaginator(10, func(ctx context.Context, offset, limit int64) (int64, int64, error) {
+		count, total, err := fetch(ctx, offset, limit)
+		fetched += count
+		return count, total, err
+	}).WithValidation(PaginationValidationError).Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if fetched != 25 {
+		t.Fatalf("unexpected fetched items, expected %d, got %d", 25, fetched)
+	}
+}
+
+func TestPaginatorInconsistent(t *testing.T) {
+	err := NewPaginator(10, pages(25, 18)).WithValidation(PaginationValidationError).Run(context.Background())
+	inconsistent, ok := err.(*ErrPaginationInconsistent)
+	if !ok {
+		t.Fatalf("unexpected error, expected *ErrPaginationInconsistent, got %+v", err)
+	}
+	if inconsistent.Expected != 25 || inconsistent.Observed != 18 {
+		t.Fatalf("unexpected counts: %+v", inconsistent)
+	}
+
+	err = NewPaginator(10, pages(25, 18)).WithValidation(PaginationValidationWarn).Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error when only warning: %+v", err)
+	}
+}
+
+func TestIterateTokens(t *testing.T) {
+	next := map[string]string{"": "a", "a": "b", "b": ""}
+	var visited []string
+	err := IterateTokens(context.Background(), func(ctx context.Context, token string) (string, error) {
+		visited = append(visited, token)
+		return next[token], nil
+	})
+	if err != nil || len(visited) != 3 || visited[1] != "a" || visited[2] != "b" {
+		t.Fatalf("unexpected pages %q, %+v", visited, err)
+	}
+
+	err = IterateTokens(context.Background(), func(ctx context.Context, token string) (string, error) {
+		return "same", nil
+	})
+	if sdkErr, ok := err.(*tcerr.TencentCloudSDKError); !ok || sdkErr.GetCode() != "ClientError.PaginationTokenRepeated" {
+		t.Fatalf("expected repeated token error, got %+v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = IterateTokens(ctx, func(ctx context.Context, token string) (string, error) {
+		cancel()
+		return token + "x", nil
+	})
+	if err != context.Canceled {
+		t.Fatalf("expected context canceled, got %+v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package profile
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+// Builder configures a ClientProfile fluently, e.g.
+//
+//	builder := profile.NewBuilder().Region("ap-guangzhou").Endpoint("cvm.tencentcloudapi.com").Timeout(30).MaxRetries(3)
+//	prof, err := builder.Build()
+//	...
+//	client, err := cvm.NewClient(credential, builder.GetRegion(), prof)
+//
+// The options left unset keep the defaults of NewClientProfile.
+// The region is not a part of the profile, it is kept by the builder and passed to NewClient.
+type Builder struct {
+	profile *ClientProfile
+	region  string
+	err     error
+}
+
+// NewBuilder returns a Builder which starts from the defaults of NewClientProfile
+func NewBuilder() *Builder {
+	return &Builder{profile: NewClientProfile()}
+}
+
+// Region sets the region of the client, which is read by GetRegion, an empty region is reported by Build
+func (b *Builder) Region(region string) *Builder {
+	if region == "" {
+		b.fail(invalidProfile("Region must not be empty"))
+	}
+	b.region = region
+	return b
+}
+
+// GetRegion returns the region set by Region, or empty if it is not set
+func (b *Builder) GetRegion() string {
+	return b.region
+}
+
+func (b *Builder) Endpoint(endpoint string) *Builder {
+	b.profile.HttpProfile.Endpoint = endpoint
+	return b
+}
+
+// RootDomain sets the root domain, an invalid domain is reported by Build
+func (b *Builder) RootDomain(domain string) *Builder {
+	if err := b.profile.HttpProfile.WithRootDomain(domain); err != nil {
+		b.fail(err)
+	}
+	return b
+}
+
+// Scheme sets the scheme, valid choices: HTTP, HTTPS
+func (b *Builder) Scheme(scheme string) *Builder {
+	b.profile.HttpProfile.Scheme = scheme
+	return b
+}
+
+// ReqMethod sets the http method, valid choices: GET, POST
+func (b *Builder) ReqMethod(method string) *Builder {
+	b.profile.HttpProfile.ReqMethod = method
+	return b
+}
+
+// Timeout sets the timeout of each request attempt in seconds
+func (b *Builder) Timeout(seconds int) *Builder {
+	b.profile.HttpProfile.ReqTimeout = seconds
+	return b
+}
+
+func (b *Builder) ProxyURL(proxyURL string) *Builder {
+	b.profile.HttpProfile.ProxyURL = proxyURL
+	return b
+}
+
+func (b *Builder) TLSConfig(config *tls.Config) *Builder {
+	b.profile.HttpProfile.TLSConfig = config
+	return b
+}
+
+// SignMethod sets the sign method, valid choices: HmacSHA1, HmacSHA256, TC3-HMAC-SHA256
+func (b *Builder) SignMethod(method string) *Builder {
+	b.profile.SignMethod = method
+	return b
+}
+
+func (b *Builder) UnsignedPayload(unsigned bool) *Builder {
+	b.profile.UnsignedPayload = unsigned
+	return b
+}
+
+// Language sets the language of the response, valid choices: zh-CN, en-US
+func (b *Builder) Language(language string) *Builder {
+	b.profile.Language = language
+	return b
+}
+
+func (b *Builder) Debug(debug bool) *Builder {
+	b.profile.Debug = debug
+	return b
+}
+
+// MaxRetries sets the max retries of both the network failures and the rate limited requests
+func (b *Builder) MaxRetries(retries int) *Builder {
+	b.profile.NetworkFailureMaxRetries = retries
+	b.profile.RateLimitExceededMaxRetries = retries
+	return b
+}
+
+// RetryDuration sets the delay before each retry of both the network failures and the rate limited requests
+func (b *Builder) RetryDuration(duration DurationFunc) *Builder {
+	b.profile.NetworkFailureRetryDuration = duration
+	b.profile.RateLimitExceededRetryDuration = duration
+	return b
+}
+
+// RetryMode sets the retry mode, valid choices: Standard, Adaptive
+func (b *Builder) RetryMode(mode string) *Builder {
+	b.profile.RetryMode = mode
+	return b
+}
+
+// Build validates the options and returns a copy of the profile, so that the builder can be
+// reused to build other profiles, the error describes the first invalid option
+func (b *Builder) Build() (*ClientProfile, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	p := b.profile
+	checks := []struct {
+		name  string
+		value string
+		valid []string
+	}{
+		{"SignMethod", p.SignMethod, []string{"HmacSHA1", "HmacSHA256", "TC3-HMAC-SHA256"}},
+		{"ReqMethod", p.HttpProfile.ReqMethod, []string{"GET", "POST"}},
+		{"Scheme", strings.ToUpper(p.HttpProfile.Scheme), []string{"HTTP", "HTTPS"}},
+		{"Language", p.Language, []string{"zh-CN", "en-US"}},
+		{"RetryMode", p.RetryMode, []string{RetryModeStandard, RetryModeAdaptive}},
+	}
+	for _, check := range checks {
+		if !contains(check.valid, check.value) {
+			return nil, invalidProfile(fmt.Sprintf("%s %q is not one of %s", check.name, check.value, strings.Join(check.valid, ", ")))
+		}
+	}
+	if p.HttpProfile.ReqTimeout < 0 {
+		return nil, invalidProfile(fmt.Sprintf("Timeout %d must not be negative", p.HttpProfile.ReqTimeout))
+	}
+	if p.NetworkFailureMaxRetries < 0 || p.RateLimitExceededMaxRetries < 0 {
+		return nil, invalidProfile("MaxRetries must not be negative")
+	}
+
+	built := *p
+	httpProfile := *p.HttpProfile
+	httpProfile.BackupDomains = append([]string(nil), p.HttpProfile.BackupDomains...)
+	built.HttpProfile = &httpProfile
+	built.RetryableErrorCodes = append([]string(nil), p.RetryableErrorCodes...)
+	return &built, nil
+}
+
+func (b *Builder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func invalidProfile(msg string) error {
+	return errors.NewTencentCloudSDKError("ClientError.InvalidProfile", msg, "")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
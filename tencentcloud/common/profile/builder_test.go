@@ -0,0 +1,57 @@
+package profile
+
+import (
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	prof, err := NewBuilder().Endpoint("cvm.tencentcloudapi.com").Timeout(30).SignMethod("HmacSHA256").MaxRetries(3).Debug(true).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if prof.HttpProfile.Endpoint != "cvm.tencentcloudapi.com" || prof.HttpProfile.ReqTimeout != 30 || prof.SignMethod != "HmacSHA256" ||
+		prof.NetworkFailureMaxRetries != 3 || prof.RateLimitExceededMaxRetries != 3 || !prof.Debug {
+		t.Fatalf("unexpected profile %+v", prof)
+	}
+	// the unset options keep the defaults
+	if prof.HttpProfile.ReqMethod != "POST" || prof.Language != "zh-CN" || prof.RetryMode != RetryModeStandard {
+		t.Fatalf("unexpected defaults %+v", prof)
+	}
+
+	invalid := map[string]*Builder{
+		"empty sign method": NewBuilder().SignMethod(""),
+		"unknown method":    NewBuilder().ReqMethod("PUT"),
+		"negative timeout":  NewBuilder().Timeout(-1),
+		"negative retries":  NewBuilder().MaxRetries(-1),
+		"root domain":       NewBuilder().RootDomain("localhost").Endpoint("cvm.tencentcloudapi.com"),
+		"empty region":      NewBuilder().Region(""),
+	}
+	for name, builder := range invalid {
+		if _, err := builder.Build(); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestBuilderRegion(t *testing.T) {
+	builder := NewBuilder().Region("ap-guangzhou").Timeout(30)
+	first, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if builder.GetRegion() != "ap-guangzhou" {
+		t.Fatalf("unexpected region %s", builder.GetRegion())
+	}
+
+	// the profiles built are not changed by the builder reused
+	second, err := builder.Timeout(60).Endpoint("cvm.tencentcloudapi.com").Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if first == second || first.HttpProfile == second.HttpProfile {
+		t.Fatal("the profile of the builder should be copied")
+	}
+	if first.HttpProfile.ReqTimeout != 30 || first.HttpProfile.Endpoint != "" || second.HttpProfile.ReqTimeout != 60 {
+		t.Fatalf("unexpected profiles %+v %+v", first.HttpProfile, second.HttpProfile)
+	}
+}
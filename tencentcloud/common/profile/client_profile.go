@@ -1,10 +1,26 @@
 package profile
 
 import (
+	"encoding/json"
 	"math"
 	"time"
 )
 
+const (
+	// RetryModeStandard retries every retryable failure up to the configured max retries.
+	RetryModeStandard = "Standard"
+	// RetryModeAdaptive draws every retry from a retry budget shared by all requests
+	// sent through the same client. The budget is refilled by successful requests,
+	// so retries stop as soon as the recent failure rate gets too high.
+	RetryModeAdaptive = "Adaptive"
+
+	// ExpiredCredentialRefresh refreshes a refreshable credential which is already expired before signing,
+	// an error is returned if it is still expired after refreshing.
+	ExpiredCredentialRefresh = "Refresh"
+	// ExpiredCredentialError returns an error immediately for an expired credential
+	ExpiredCredentialError = "Error"
+)
+
 type DurationFunc func(index int) time.Duration
 
 func ConstantDurationFunc(duration time.Duration) DurationFunc {
@@ -22,18 +38,55 @@ type ClientProfile struct {
 	HttpProfile *HttpProfile
 	// Valid choices: HmacSHA1, HmacSHA256, TC3-HMAC-SHA256.
 	// Default value is TC3-HMAC-SHA256.
-	SignMethod      string
-	UnsignedPayload bool
+	SignMethod string
+	// DisableSignatureV1 makes the requests fail with the error code ClientError.SignatureV1Disabled
+	// if the sign method is HmacSHA1 or HmacSHA256, including the one set by Client.WithSignatureMethod.
+	// Default value is false.
+	DisableSignatureV1 bool
+	UnsignedPayload    bool
 	// Valid choices: zh-CN, en-US.
 	// Default value is zh-CN.
 	Language string
 	Debug    bool
 
-	// define how to retry request
+	// define how to retry request, the duration funcs are not exported by MarshalJSON
 	NetworkFailureMaxRetries       int
-	NetworkFailureRetryDuration    DurationFunc
+	NetworkFailureRetryDuration    DurationFunc `json:"-"`
 	RateLimitExceededMaxRetries    int
-	RateLimitExceededRetryDuration DurationFunc
+	RateLimitExceededRetryDuration DurationFunc `json:"-"`
+	// RetryableErrorCodes are the transient error codes of the API, e.g. InternalError or ResourceInsufficient,
+	// which are retried like RequestLimitExceeded, with RateLimitExceededMaxRetries and RateLimitExceededRetryDuration.
+	// They are only retried for the requests with a ClientToken field, which is kept by the retries, or of an action
+	// registered by common.RegisterIdempotentActions, since the failed request may have taken effect,
+	// see RetryWithoutClientToken. Default value is nil.
+	RetryableErrorCodes []string
+	// RetryWithoutClientToken opts in to retry the network failures, the 5xx responses and RetryableErrorCodes
+	// of the requests without a ClientToken field, which are sent only once by default unless their actions are
+	// registered by common.RegisterIdempotentActions. A warning is logged on every such retry of a non-idempotent action,
+	// e.g. CreateStaff, which may take effect more than once, the read actions are retried silently.
+	// Default value is false.
+	RetryWithoutClientToken bool
+	// MaxRetryAfter caps the delay requested by the Retry-After header of a 5xx or rate limited response,
+	// which replaces the retry duration above. Default value is 0, which means 30 seconds.
+	MaxRetryAfter time.Duration
+	// MaxRetryElapsedTime bounds the time taken by a call and its retries, including the sleeps between them.
+	// A retry which would start after it elapses is not made, the last error is returned instead.
+	// The deadline of the context of the request still applies, whichever is shorter wins.
+	// Default value is 0, which means the retries are bounded by the max retries only.
+	MaxRetryElapsedTime time.Duration
+	// Valid choices: Standard, Adaptive.
+	// Default value is Standard.
+	RetryMode string
+	// Valid choices: Refresh, Error.
+	// Default value is Refresh.
+	ExpiredCredentialPolicy string
+	// VerifyResponseChecksum verifies the response body against the Content-MD5 or
+	// X-TC-Content-SHA256 header if the server returns one. Default value is false.
+	VerifyResponseChecksum bool
+	// StrictResponseValidation rejects the json response without Response.RequestId, which is
+	// probably returned by a proxy instead of the API, with the error code ClientError.MissingRequestId.
+	// Default value is false.
+	StrictResponseValidation bool
 }
 
 func NewClientProfile() *ClientProfile {
@@ -43,5 +96,42 @@ func NewClientProfile() *ClientProfile {
 		UnsignedPayload: false,
 		Language:        "zh-CN",
 		Debug:           false,
+		RetryMode:       RetryModeStandard,
+
+		ExpiredCredentialPolicy: ExpiredCredentialRefresh,
+	}
+}
+
+// clientProfileJSON has the fields of ClientProfile without its methods, so that it is encoded by default
+type clientProfileJSON ClientProfile
+
+// MarshalJSON exports the profile, e.g. to share it in a bug report, the region and the credential are not
+// part of it, see Client.DumpConfig. The password of HttpProfile.ProxyURL is redacted, the duration funcs
+// and HttpProfile.TLSConfig are not exported, they take the default values once the profile is imported.
+func (p *ClientProfile) MarshalJSON() ([]byte, error) {
+	return json.Marshal((*clientProfileJSON)(p))
+}
+
+// UnmarshalJSON imports the profile exported by MarshalJSON, the fields absent from data are left unchanged,
+// except that the empty SignMethod, Language, RetryMode and ExpiredCredentialPolicy take the defaults of
+// NewClientProfile, and HttpProfile is created by NewHttpProfile if it is nil, so that a profile imported
+// into &ClientProfile{} is the same as one imported into NewClientProfile().
+func (p *ClientProfile) UnmarshalJSON(data []byte) error {
+	defaults := NewClientProfile()
+	if p.HttpProfile == nil {
+		p.HttpProfile = defaults.HttpProfile
+	}
+	if p.SignMethod == "" {
+		p.SignMethod = defaults.SignMethod
+	}
+	if p.Language == "" {
+		p.Language = defaults.Language
+	}
+	if p.RetryMode == "" {
+		p.RetryMode = defaults.RetryMode
+	}
+	if p.ExpiredCredentialPolicy == "" {
+		p.ExpiredCredentialPolicy = defaults.ExpiredCredentialPolicy
 	}
+	return json.Unmarshal(data, (*clientProfileJSON)(p))
 }
@@ -0,0 +1,35 @@
+package profile
+
+import (
+	"testing"
+)
+
+func TestWithRootDomain(t *testing.T) {
+	valid := map[string]string{
+		RootDomainIntl:               RootDomainIntl,
+		RootDomainShanghaiFsi:        RootDomainShanghaiFsi,
+		" Internal.Example.COM ":     "internal.example.com",
+		"a-b.tencentcloudapi.com":    "a-b.tencentcloudapi.com",
+		"api.xn--fiqs8s.example.com": "api.xn--fiqs8s.example.com",
+	}
+	for domain, expected := range valid {
+		prof := NewHttpProfile()
+		if err := prof.WithRootDomain(domain); err != nil {
+			t.Fatalf("unexpected error for %q: %+v", domain, err)
+		}
+		if prof.RootDomain != expected {
+			t.Fatalf("unexpected root domain, expected %s, got %s", expected, prof.RootDomain)
+		}
+	}
+
+	invalid := []string{"", "localhost", "https://tencentcloudapi.com", "tencentcloudapi.com/", "a..com", "-a.com", "a_b.com"}
+	for _, domain := range invalid {
+		prof := NewHttpProfile()
+		if err := prof.WithRootDomain(domain); err == nil {
+			t.Fatalf("expected error for %q", domain)
+		}
+		if prof.RootDomain != "" {
+			t.Fatalf("root domain should be unchanged for %q, got %s", domain, prof.RootDomain)
+		}
+	}
+}
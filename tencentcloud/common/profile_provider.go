@@ -10,9 +10,14 @@ import (
 
 const (
 	EnvCredentialFile = "TENCENTCLOUD_CREDENTIALS_FILE"
+	// EnvProfile selects the profile to read from the credentials file
+	EnvProfile = "TENCENTCLOUD_PROFILE"
+
+	defaultProfileName = "default"
 )
 
 type ProfileProvider struct {
+	profileName string
 }
 
 // DefaultProfileProvider return a default Profile  provider
@@ -20,10 +25,32 @@ type ProfileProvider struct {
 //  1. The value of the environment variable TENCENTCLOUD_CREDENTIALS_FILE
 //  2. linux: ~/.tencentcloud/credentials
 // 	  windows: \c:\Users\NAME\.tencentcloud\credentials
+//
+// profile name :
+//  1. The value of the environment variable TENCENTCLOUD_PROFILE
+//  2. default
 func DefaultProfileProvider() *ProfileProvider {
 	return &ProfileProvider{}
 }
 
+// NewProfileProvider return a Profile provider which reads the credential
+// from the section named profileName of the credentials file, e.g. [prod]
+func NewProfileProvider(profileName string) *ProfileProvider {
+	return &ProfileProvider{profileName: profileName}
+}
+
+// getProfileName returns the profile to read, the explicitly specified one takes precedence
+// over the environment variable, and the default profile is used when neither is set
+func (p *ProfileProvider) getProfileName() string {
+	if p.profileName != "" {
+		return p.profileName
+	}
+	if name := os.Getenv(EnvProfile); name != "" {
+		return name
+	}
+	return defaultProfileName
+}
+
 // getHomePath return home directory according to the system.
 // if the environmental variables does not exist, it will return empty string
 func getHomePath() string {
@@ -82,11 +109,15 @@ func (p *ProfileProvider) GetCredential() (CredentialIface, error) {
 		return nil, err
 	}
 
-	sId := cfg.section("default").key("secret_id").string()
-	sKey := cfg.section("default").key("secret_key").string()
+	name := p.getProfileName()
+	if !cfg.has(name) {
+		return nil, tcerr.NewTencentCloudSDKError(creErr, "Failed to find profile \""+name+"\" in profile file "+path, "")
+	}
+	sId := cfg.section(name).key("secret_id").string()
+	sKey := cfg.section(name).key("secret_key").string()
 	// if sId and sKey is "", but the credential file exist, means an error
 	if sId == "" || sKey == "" {
-		return nil, tcerr.NewTencentCloudSDKError(creErr, "Failed to parse profile file,please confirm whether it contains \"secret_id\" and \"secret_key\" in section: \"default\" ", "")
+		return nil, tcerr.NewTencentCloudSDKError(creErr, "Failed to parse profile file,please confirm whether it contains \"secret_id\" and \"secret_key\" in section: \""+name+"\" ", "")
 	}
 	return &Credential{
 		SecretId:  sId,
@@ -0,0 +1,37 @@
+package common
+
+import (
+	"os"
+	"testing"
+)
+
+func TestProfileProvider_GetCredential(t *testing.T) {
+	os.Setenv(EnvCredentialFile, "./testdata_profiles.ini")
+	defer os.Unsetenv(EnvCredentialFile)
+
+	tests := []struct {
+		name     string
+		provider *ProfileProvider
+		env      string
+		want     string
+		wantErr  bool
+	}{
+		{"default profile", DefaultProfileProvider(), "", "default-id", false},
+		{"profile from env", DefaultProfileProvider(), "staging", "staging-id", false},
+		{"profile from parameter", NewProfileProvider("staging"), "default", "staging-id", false},
+		{"missing profile", NewProfileProvider("prod"), "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(EnvProfile, tt.env)
+			defer os.Unsetenv(EnvProfile)
+			got, err := tt.provider.GetCredential()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetCredential() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.GetSecretId() != tt.want {
+				t.Fatalf("GetCredential() got = %v, want %v", got.GetSecretId(), tt.want)
+			}
+		})
+	}
+}
@@ -10,11 +10,13 @@ var (
 
 // Provider provide credential to build client.
 //
-// Now there are four kinds provider:
+// Now there are six kinds provider:
 //  EnvProvider : get credential from your Variable environment
 //  ProfileProvider : get credential from your profile
+//  FileProvider : get credential from an INI or JSON credentials file
 //	CvmRoleProvider : get credential from your cvm role
 //  RoleArnProvider : get credential from your role arn
+//  AssumeRoleProvider : get credential from your role arn, signed by another credential
 type Provider interface {
 	// GetCredential get the credential interface
 	GetCredential() (CredentialIface, error)
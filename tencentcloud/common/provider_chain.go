@@ -1,6 +1,8 @@
 package common
 
 import (
+	"errors"
+
 	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
 )
 
@@ -17,18 +19,20 @@ func NewProviderChain(providers []Provider) Provider {
 
 // DefaultProviderChain returns a default provider chain and try to get credentials in the following order:
 //  1. Environment variable
-//  2. Profile
-//  3. CvmRole
+//  2. Credentials file, INI or JSON, see FileProvider
+//  3. Profile
+//  4. CvmRole
 // If you want to customize the search order, please use the function NewProviderChain
 func DefaultProviderChain() Provider {
-	return NewProviderChain([]Provider{DefaultEnvProvider(), DefaultProfileProvider(), DefaultCvmRoleProvider()})
+	return NewProviderChain([]Provider{DefaultEnvProvider(), DefaultFileProvider(), DefaultProfileProvider(), DefaultCvmRoleProvider()})
 }
 
 func (c *ProviderChain) GetCredential() (CredentialIface, error) {
 	for _, provider := range c.Providers {
 		cred, err := provider.GetCredential()
 		if err != nil {
-			if err == envNotSet || err == fileDoseNotExist || err == noCvmRole {
+			var notFound *CredentialFileNotFoundError
+			if err == envNotSet || err == fileDoseNotExist || err == noCvmRole || errors.As(err, &notFound) {
 				continue
 			} else {
 				return nil, err
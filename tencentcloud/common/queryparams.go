@@ -0,0 +1,24 @@
+package common
+
+// DefaultExcludedQueryParams returns the common params which are excluded from the query string of
+// a GET request signed by signature v3, since they are sent in the X-TC-* headers instead.
+func DefaultExcludedQueryParams() []string {
+	return []string{"Action", "Version", "Nonce", "Region", "RequestClient", "Timestamp"}
+}
+
+// WithExcludedQueryParams replaces the params excluded from the query string of a GET request signed by
+// signature v3, e.g. to keep Nonce in the query string for an API which requires it. The query string
+// is signed as it is sent, so the signature stays valid whichever params are excluded.
+// Calling it without names restores DefaultExcludedQueryParams, an empty non-nil slice excludes nothing.
+func (c *Client) WithExcludedQueryParams(names ...string) *Client {
+	c.excludedQueryParams = names
+	return c
+}
+
+// excludedQueryParamsOrDefault returns the params excluded from the query string of a v3 GET request
+func (c *Client) excludedQueryParamsOrDefault() []string {
+	if c.excludedQueryParams == nil {
+		return DefaultExcludedQueryParams()
+	}
+	return c.excludedQueryParams
+}
@@ -2,6 +2,8 @@ package common
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
@@ -15,44 +17,181 @@ import (
 const (
 	codeLimitExceeded = "RequestLimitExceeded"
 	tplRateLimitRetry = "[WARN] rate limit exceeded, retrying (%d/%d) in %f seconds: %s"
+	tplErrorCodeRetry = "[WARN] retryable error code, retrying (%d/%d) in %f seconds: %s"
+
+	codeHttpStatusCode  = "ClientError.HttpStatusCodeError"
+	codeParseJson       = "ClientError.ParseJsonError"
+	tplServerErrorRetry = "[WARN] server error, retrying (%d/%d) in %f seconds: %s"
 )
 
-func (c *Client) sendWithRateLimitRetry(req *http.Request, retryable bool) (resp *http.Response, err error) {
+func (c *Client) sendWithRateLimitRetry(req *http.Request, request tchttp.Request, response tchttp.Response, trace *timingTrace) (resp *http.Response, err error) {
+	if c.dryRun != nil {
+		return nil, c.captureDryRun(req)
+	}
+	trace.signed()
+	retryable, unsafeAction := c.retryPolicy(request)
+	stats := &retryStats{start: time.Now(), hook: c.retryHook, unsafeAction: unsafeAction, trace: trace}
+	defer func() {
+		c.health.record(err)
+		stats.fill(response)
+		trace.fill(response)
+	}()
+
 	// make sure maxRetries is more than 0
 	maxRetries := maxInt(c.profile.RateLimitExceededMaxRetries, 0)
 	durationFunc := safeDurationFunc(c.profile.RateLimitExceededRetryDuration)
+	// 5xx responses are retried like the network failures
+	maxServerRetries := maxInt(c.profile.NetworkFailureMaxRetries, 0)
+	serverDurationFunc := safeDurationFunc(c.profile.NetworkFailureRetryDuration)
 
 	var shadow []byte
-	for idx := 0; idx <= maxRetries; idx++ {
-		resp, err = c.sendWithNetworkFailureRetry(req, retryable)
+	for idx, serverIdx := 0, 0; ; {
+		resp, err = c.sendWithNetworkFailureRetry(req, request, retryable, stats)
 		if err != nil {
 			return
 		}
+		tchttp.SetHeaders(response, resp.Header)
 
-		resp.Body, shadow = shadowRead(resp.Body)
+		// the binary body and the body of a StreamResponse are streamed to the caller without buffering
+		if tchttp.IsStreamBody(resp, response) {
+			if c.profile.VerifyResponseChecksum {
+				resp.Body = NewChecksumReader(resp.Body, resp.Header)
+			}
+			if streaming, ok := response.(*streamingResponse); ok {
+				streaming.httpResponse = resp
+			}
+			return resp, nil
+		}
 
-		err = tchttp.ParseErrorFromHTTPResponse(shadow)
-		// should not sleep on last request
-		if err, ok := err.(*errors.TencentCloudSDKError); ok && err.Code == codeLimitExceeded && idx < maxRetries {
-			duration := durationFunc(idx)
-			if c.debug {
-				log.Printf(tplRateLimitRetry, idx, maxRetries, duration.Seconds(), err.Error())
+		if limit := c.httpProfile.MaxResponseBodyBytes; limit > 0 {
+			resp.Body = tchttp.LimitBody(resp.Body, limit)
+		}
+		var readErr error
+		resp.Body, shadow, readErr = shadowRead(resp.Body)
+		if _, ok := readErr.(*errors.TencentCloudSDKError); ok {
+			resp.Body.Close()
+			return nil, tchttp.FillRequestId(readErr, tchttp.GetRequestIdFromHeader(resp.Header))
+		}
+		if c.profile.VerifyResponseChecksum && shadow != nil {
+			if err = verifyChecksum(resp.Header, shadow); err != nil {
+				return nil, err
 			}
+		}
 
-			time.Sleep(duration)
-			continue
+		if c.profile.StrictResponseValidation && resp.StatusCode == http.StatusOK {
+			if err = tchttp.CheckRequestId(shadow); err != nil {
+				return nil, err
+			}
+		}
+
+		requestId := tchttp.GetRequestIdFromHeader(resp.Header)
+		err = tchttp.FillRequestId(tchttp.ParseErrorFromHTTPResponse(shadow), requestId)
+		if isSignatureExpire(err) {
+			c.updateClockOffset(resp.Header)
+		}
+		err = serverError(resp, shadow, err, requestId)
+		sdkErr, _ := err.(*errors.TencentCloudSDKError)
+		// 5xx responses are retried only if the request is retryable
+		retryServer := sdkErr != nil && sdkErr.Code == codeHttpStatusCode && retryable
+		retryCode := sdkErr != nil && c.isRetryableCode(sdkErr.Code, retryable)
+		if sdkErr != nil && c.retryDecider != nil {
+			retry := c.retryDecider(request, resp, err)
+			// the body may be read by the decider
+			resp.Body = ioutil.NopCloser(bytes.NewReader(shadow))
+			retryServer = retry && sdkErr.Code == codeHttpStatusCode
+			retryCode = retry && !retryServer
 		}
+		if retryCode && maxRetries > 0 && req.Context().Err() == nil {
+			// should not sleep on last request
+			duration, ok := c.retryAfter(resp.Header)
+			if !ok {
+				duration = durationFunc(idx)
+			}
+			if idx < maxRetries && c.withinRetryElapsedTime(stats, duration) && retryBeforeDeadline(req.Context(), duration) && c.acquireRetry(sdkErr) {
+				if c.debug {
+					tpl := tplRateLimitRetry
+					if sdkErr.Code != codeLimitExceeded {
+						tpl = tplErrorCodeRetry
+					}
+					log.Printf(tpl, idx, maxRetries, duration.Seconds(), sdkErr.Error())
+				}
 
+				idx++
+				stats.retry(req.Context(), sdkErr.Code, sdkErr, duration)
+				continue
+			}
+			c.onRetryExhausted(stats, err)
+		}
+		if retryServer && maxServerRetries > 0 && req.Context().Err() == nil {
+			duration, ok := c.retryAfter(resp.Header)
+			if !ok {
+				duration = serverDurationFunc(serverIdx)
+			}
+			if serverIdx < maxServerRetries && c.withinRetryElapsedTime(stats, duration) && retryBeforeDeadline(req.Context(), duration) && c.acquireRetry(sdkErr) {
+				if c.debug {
+					log.Printf(tplServerErrorRetry, serverIdx, maxServerRetries, duration.Seconds(), resp.Status)
+				}
+
+				serverIdx++
+				stats.retry(req.Context(), sdkErr.Code, sdkErr, duration)
+				continue
+			}
+			c.onRetryExhausted(stats, err)
+		}
+
+		if err == nil && c.retryBudget != nil {
+			c.retryBudget.release()
+		}
 		return resp, err
 	}
+}
+
+// retryBeforeDeadline reports whether the retry after duration is sent before the deadline of ctx expires,
+// the retry can not succeed otherwise
+func retryBeforeDeadline(ctx context.Context, duration time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Now().Add(duration).Before(deadline)
+}
+
+// isRetryableCode reports whether the API error code is retried, RequestLimitExceeded always is,
+// the codes of RetryableErrorCodes are retried only if the request is retryable
+func (c *Client) isRetryableCode(code string, retryable bool) bool {
+	if code == codeLimitExceeded {
+		return true
+	}
+	if !retryable {
+		return false
+	}
+	for _, retryableCode := range c.profile.RetryableErrorCodes {
+		if code == retryableCode {
+			return true
+		}
+	}
+	return false
+}
 
-	return resp, err
+// serverError returns a ClientError.HttpStatusCodeError for the 5xx response unless its body is an API error,
+// the body of such a response is often not json, e.g. a html page returned by the gateway
+func serverError(resp *http.Response, body []byte, err error, requestId string) error {
+	if resp.StatusCode < http.StatusInternalServerError {
+		return err
+	}
+	if sdkErr, ok := err.(*errors.TencentCloudSDKError); ok && sdkErr.Code != codeParseJson {
+		return err
+	}
+	msg := fmt.Sprintf("Request fail with http status code: %s, with body: %s", resp.Status, body)
+	return errors.NewTencentCloudSDKError(codeHttpStatusCode, msg, requestId)
 }
 
-func shadowRead(reader io.ReadCloser) (io.ReadCloser, []byte) {
+// shadowRead buffers the body, the error is returned along with the unread reader,
+// the client errors, e.g. ClientError.ResponseBodyTooLarge, are reported as they are,
+// the others are reported by parsing the response
+func shadowRead(reader io.ReadCloser) (io.ReadCloser, []byte, error) {
 	val, err := ioutil.ReadAll(reader)
 	if err != nil {
-		return reader, nil
+		return reader, nil, err
 	}
-	return ioutil.NopCloser(bytes.NewBuffer(val)), val
+	// the body is fully buffered, close it to release the connection and the context of the attempt
+	reader.Close()
+	return ioutil.NopCloser(bytes.NewBuffer(val)), val, nil
 }
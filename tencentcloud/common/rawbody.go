@@ -0,0 +1,27 @@
+package common
+
+import (
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// WithRawBody keeps the raw json body of the responses up to limit bytes, which is returned by GetRawBody
+// of the response, e.g. to log or archive the response as is. The body larger than limit is not kept.
+// It is disabled by default, pass a non-positive limit to disable it.
+func (c *Client) WithRawBody(limit int64) *Client {
+	if limit < 0 {
+		limit = 0
+	}
+	c.rawBodyLimit = limit
+	return c
+}
+
+// dropRawBody releases the raw body of response unless it is kept by WithRawBody
+func (c *Client) dropRawBody(response tchttp.Response) {
+	raw, ok := response.(interface{ GetRawBody() []byte })
+	if !ok {
+		return
+	}
+	if body := raw.GetRawBody(); body != nil && int64(len(body)) > c.rawBodyLimit {
+		tchttp.SetRawBody(response, nil)
+	}
+}
@@ -0,0 +1,89 @@
+package common
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// readCache coalesces identical read calls, the successful results are kept for ttl,
+// so that the identical calls made within the window are served without sending requests.
+type readCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	actions map[string]bool
+	entries *ttlStore
+	calls   callGroup
+}
+
+func newReadCache(ttl time.Duration, actions []string) *readCache {
+	cache := &readCache{
+		ttl:     ttl,
+		actions: make(map[string]bool, len(actions)),
+		entries: newTTLStore(),
+	}
+	for _, action := range actions {
+		cache.actions[action] = true
+	}
+	return cache
+}
+
+// WithReadCache coalesces the identical calls of actions made within ttl, the first call sends
+// the request and the successful result is reused by the others, errors are never cached.
+// Calls are identical if they have the same domain, version, action and parameters.
+// Only read actions, e.g. DescribeXxx, should be listed since the result may be stale up to ttl.
+//
+// The cache is disabled by default, it is bounded as NewMemoryResponseCache.
+// Pass a non-positive ttl or no action to disable it.
+func (c *Client) WithReadCache(ttl time.Duration, actions ...string) *Client {
+	if ttl <= 0 || len(actions) == 0 {
+		c.readCache = nil
+		return c
+	}
+	c.readCache = newReadCache(ttl, actions)
+	return c
+}
+
+func (c *Client) sendWithReadCache(request tchttp.Request, response tchttp.Response) error {
+	payload, err := requestPayload(request)
+	if err != nil {
+		return c.send(request, response)
+	}
+	key := request.GetDomain() + "|" + request.GetVersion() + "|" + request.GetAction() + "|" + string(payload)
+
+	rc := c.readCache
+	rc.mu.Lock()
+	body, ok := rc.entries.get(key)
+	rc.mu.Unlock()
+	if ok {
+		return (&sharedResult{body: body}).parse(response)
+	}
+	result, err := sendShared(&rc.calls, key, request, response, c.send)
+	if result != nil {
+		rc.mu.Lock()
+		rc.entries.set(key, result.body, rc.ttl)
+		rc.mu.Unlock()
+	}
+	return err
+}
+
+// requestPayload returns the parameters which identify a call of request,
+// i.e. the json body and the query parameters of a POST request
+func requestPayload(request tchttp.Request) ([]byte, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	if cr, ok := request.(*tchttp.CommonRequest); ok {
+		params, err := cr.GetQueryParams()
+		if err != nil {
+			return nil, err
+		}
+		if len(params) > 0 {
+			payload = append(payload, "?"+tchttp.GetCanonicalQueryString(params)...)
+		}
+	}
+	return payload, nil
+}
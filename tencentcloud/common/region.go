@@ -0,0 +1,51 @@
+package common
+
+import (
+	"os"
+	"sync"
+
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// EnvRegion is the environment variable of the region used when no region is passed to the client
+const EnvRegion = "TENCENTCLOUD_REGION"
+
+// resolveRegion returns region, or the value of the environment variable TENCENTCLOUD_REGION if region is empty
+func resolveRegion(region string) string {
+	if region != "" {
+		return region
+	}
+	return os.Getenv(EnvRegion)
+}
+
+var (
+	regionalServicesMu sync.RWMutex
+	// regionalServices are the services which reject the requests without a region
+	regionalServices = map[string]bool{}
+)
+
+// RegisterRegionalService marks service as region scoped, so that its requests are rejected locally
+// when the client has no region, rather than by the server with a vague message.
+// It is called by the service packages from their metadata, the global services, e.g. cam, are not registered.
+func RegisterRegionalService(service string) {
+	regionalServicesMu.Lock()
+	regionalServices[service] = true
+	regionalServicesMu.Unlock()
+}
+
+// IsRegionalService reports whether service is registered by RegisterRegionalService
+func IsRegionalService(service string) bool {
+	regionalServicesMu.RLock()
+	defer regionalServicesMu.RUnlock()
+	return regionalServices[service]
+}
+
+// checkRegion rejects the request of a region scoped service when the client has no region
+func (c *Client) checkRegion(request tchttp.Request) error {
+	if c.GetRegion() != "" || !IsRegionalService(request.GetService()) {
+		return nil
+	}
+	msg := "region is empty but service " + request.GetService() + " is region scoped, please pass the region or set the environment variable " + EnvRegion
+	return tcerr.NewTencentCloudSDKError("ClientError.RegionNotFound", msg, "")
+}
@@ -56,3 +56,24 @@ const (
 	// 多伦多
 	Toronto = "na-toronto"
 )
+
+// all is the known regions in the order of the constants above
+var all = []string{
+	Bangkok, Beijing, Chengdu, Chongqing, Guangzhou, GuangzhouOpen, HongKong, Mumbai, Seoul, Shanghai,
+	Nanjing, ShanghaiFSI, ShenzhenFSI, Singapore, Tokyo, Frankfurt, Moscow, Ashburn, SiliconValley, Toronto,
+}
+
+// All returns the known regions, a region not listed may still be supported by a service launched recently
+func All() []string {
+	return append([]string(nil), all...)
+}
+
+// IsKnown reports whether region is one of the known regions, e.g. it is false for the typo ap-shanghai1
+func IsKnown(region string) bool {
+	for _, r := range all {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package common
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// ResponseCache stores the raw bodies of the successful responses, it must be safe for concurrent use.
+// The key is a hash of the domain, version, action, region and parameters of the request.
+type ResponseCache interface {
+	// Get returns the body stored for key, ok is false if it is missing or expired
+	Get(key string) (body []byte, ok bool)
+	// Set stores body for key, it expires after ttl
+	Set(key string, body []byte, ttl time.Duration)
+	// Delete removes key, it is called when a call of key fails
+	Delete(key string)
+}
+
+// readActionPrefixes are the prefixes of the read only actions cached by default
+var readActionPrefixes = []string{"Describe", "Get", "List", "Query", "Inquiry", "Inquire"}
+
+// IsReadAction reports whether action is a read only action by its name, e.g. DescribeInstances
+func IsReadAction(action string) bool {
+	for _, prefix := range readActionPrefixes {
+		if strings.HasPrefix(action, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+type memoryResponseCache struct {
+	mu      sync.Mutex
+	entries *ttlStore
+}
+
+// NewMemoryResponseCache returns a ResponseCache kept in memory, it holds at most 1024 responses,
+// the expired ones are purged when it is full, then the one which expires first is evicted.
+func NewMemoryResponseCache() ResponseCache {
+	return &memoryResponseCache{entries: newTTLStore()}
+}
+
+func (m *memoryResponseCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.entries.get(key)
+}
+
+func (m *memoryResponseCache) Set(key string, body []byte, ttl time.Duration) {
+	m.mu.Lock()
+	m.entries.set(key, body, ttl)
+	m.mu.Unlock()
+}
+
+func (m *memoryResponseCache) Delete(key string) {
+	m.mu.Lock()
+	m.entries.delete(key)
+	m.mu.Unlock()
+}
+
+type responseCache struct {
+	cache   ResponseCache
+	ttl     time.Duration
+	actions map[string]bool
+}
+
+func (rc *responseCache) cacheable(action string) bool {
+	if len(rc.actions) > 0 {
+		return rc.actions[action]
+	}
+	return IsReadAction(action)
+}
+
+// WithResponseCache caches the successful responses of the read actions in cache for ttl,
+// a call which hits the cache sends no request and gets a copy of the cached response.
+// The read actions are those listed in actions, or those accepted by IsReadAction if none is listed.
+// A failed call removes its entry, errors are never cached.
+// Since the result may be stale up to ttl, only the actions whose result rarely changes should be cached.
+//
+// The cache is disabled by default, pass a nil cache or a non-positive ttl to disable it.
+func (c *Client) WithResponseCache(cache ResponseCache, ttl time.Duration, actions ...string) *Client {
+	if cache == nil || ttl <= 0 {
+		c.responseCache = nil
+		return c
+	}
+	rc := &responseCache{cache: cache, ttl: ttl, actions: make(map[string]bool, len(actions))}
+	for _, action := range actions {
+		rc.actions[action] = true
+	}
+	c.responseCache = rc
+	return c
+}
+
+func responseCacheKey(request tchttp.Request) (string, error) {
+	payload, err := requestPayload(request)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, part := range []string{request.GetDomain(), request.GetVersion(), request.GetAction(), request.GetParams()["Region"]} {
+		h.Write([]byte(part))
+		h.Write([]byte{'|'})
+	}
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (c *Client) sendWithResponseCache(request tchttp.Request, response tchttp.Response, send func(tchttp.Request, tchttp.Response) error) error {
+	key, err := responseCacheKey(request)
+	if err != nil {
+		return send(request, response)
+	}
+	if body, ok := c.responseCache.cache.Get(key); ok {
+		// the response is parsed from a copy, so it shares nothing with the cache
+		return tchttp.ParseFromHttpResponse(&http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(bytes.NewReader(append([]byte(nil), body...))),
+		}, response)
+	}
+	if err := send(request, response); err != nil {
+		c.responseCache.cache.Delete(key)
+		return err
+	}
+	if raw, ok := response.(interface{ GetRawBody() []byte }); ok && raw.GetRawBody() != nil {
+		c.responseCache.cache.Set(key, append([]byte(nil), raw.GetRawBody()...), c.responseCache.ttl)
+	}
+	return nil
+}
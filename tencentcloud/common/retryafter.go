@@ -0,0 +1,56 @@
+package common
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultMaxRetryAfter caps the Retry-After delay if ClientProfile.MaxRetryAfter is not set
+const defaultMaxRetryAfter = 30 * time.Second
+
+// retryAfter returns the delay requested by the Retry-After header of the response,
+// capped by ClientProfile.MaxRetryAfter, false is returned if the header is absent or invalid
+func (c *Client) retryAfter(header http.Header) (time.Duration, bool) {
+	delay, ok := parseRetryAfter(header.Get("Retry-After"), c.now())
+	if !ok {
+		return 0, false
+	}
+	maxDelay := c.profile.MaxRetryAfter
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxRetryAfter
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay, true
+}
+
+// parseRetryAfter parses the value of the Retry-After header,
+// which is either the delay in seconds or a HTTP-date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		if seconds > int64(math.MaxInt64/time.Second) {
+			return math.MaxInt64, true
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	delay := date.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+	return delay, true
+}
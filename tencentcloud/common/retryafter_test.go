@@ -0,0 +1,30 @@
+package common
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	examples := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{" 0 ", 0, true},
+		{"-1", 0, false},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"Tue, 1 Jun 2021", 0, false},
+	}
+	for _, e := range examples {
+		delay, ok := parseRetryAfter(e.value, now)
+		if ok != e.ok || delay != e.expected {
+			t.Fatalf("parse %q: expected %v %v, got %v %v", e.value, e.expected, e.ok, delay, ok)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package common
+
+import (
+	"log"
+	"sync"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
+)
+
+const (
+	// the budget starts full, every retry costs retryBudgetCost credits
+	// and every successful request refunds retryBudgetRefill credit,
+	// which allows roughly one retry per five successful requests
+	retryBudgetCapacity = 500
+	retryBudgetCost     = 5
+	retryBudgetRefill   = 1
+
+	tplRetryBudgetExhausted = "[WARN] retry budget exhausted, give up retrying: %s"
+)
+
+// retryBudget is a token bucket of retry credits shared by all requests of a client.
+type retryBudget struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	cost     float64
+	refill   float64
+	// exhausted counts the retries rejected by the budget
+	exhausted uint64
+}
+
+func newRetryBudget(capacity, cost, refill float64) *retryBudget {
+	return &retryBudget{
+		capacity: capacity,
+		tokens:   capacity,
+		cost:     cost,
+		refill:   refill,
+	}
+}
+
+// acquire withdraws the cost of one retry, it returns false if the budget is not enough
+func (b *retryBudget) acquire() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < b.cost {
+		b.exhausted++
+		return false
+	}
+	b.tokens -= b.cost
+	return true
+}
+
+// release refills the budget after a successful request
+func (b *retryBudget) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens += b.refill
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
+
+func (b *retryBudget) level() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens
+}
+
+func (b *retryBudget) exhaustedCount() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.exhausted
+}
+
+// open reports whether the budget is not enough for one more retry
+func (b *retryBudget) open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens < b.cost
+}
+
+// newRetryBudgetWithRatio returns a budget which allows ratio retries per successful request
+// in the long run, with a burst of retryBudgetCapacity / retryBudgetCost retries
+func newRetryBudgetWithRatio(ratio float64) *retryBudget {
+	return newRetryBudget(retryBudgetCapacity, retryBudgetCost, ratio*retryBudgetCost)
+}
+
+// WithRetryBudget draws every retry of the client from a token bucket refilled by the successful requests,
+// so that only about ratio retries per successful request are made, e.g. 0.1 for one retry per ten successes,
+// when the failures are widespread. The bucket starts full and allows a burst of 100 retries.
+// Once it is exhausted, the failures are returned at once without retrying, and counted by
+// RetryBudgetExhausted and BreakerState. It replaces the default budget of the adaptive retry mode
+// and is kept by WithProfile. Pass a non-positive ratio to restore the budget of the profile.
+func (c *Client) WithRetryBudget(ratio float64) *Client {
+	c.retryBudgetRatio = ratio
+	c.resetRetryBudget()
+	return c
+}
+
+func (c *Client) resetRetryBudget() {
+	switch {
+	case c.retryBudgetRatio > 0:
+		c.retryBudget = newRetryBudgetWithRatio(c.retryBudgetRatio)
+	case c.profile != nil && c.profile.RetryMode == profile.RetryModeAdaptive:
+		c.retryBudget = newRetryBudget(retryBudgetCapacity, retryBudgetCost, retryBudgetRefill)
+	default:
+		c.retryBudget = nil
+	}
+}
+
+// acquireRetry reports whether the client is allowed to retry once more,
+// it always returns true unless a retry budget is working, see WithRetryBudget.
+func (c *Client) acquireRetry(reason error) bool {
+	if c.retryBudget == nil || c.retryBudget.acquire() {
+		return true
+	}
+	if c.debug {
+		log.Printf(tplRetryBudgetExhausted, reason.Error())
+	}
+	return false
+}
+
+// RetryBudgetLevel returns the retry credits currently available in the adaptive retry budget,
+// enabled is false when neither the adaptive retry mode nor WithRetryBudget is enabled.
+func (c *Client) RetryBudgetLevel() (level float64, enabled bool) {
+	if c.retryBudget == nil {
+		return 0, false
+	}
+	return c.retryBudget.level(), true
+}
+
+// RetryBudgetExhausted returns the number of retries rejected by the retry budget,
+// enabled is false when no retry budget is working.
+func (c *Client) RetryBudgetExhausted() (count uint64, enabled bool) {
+	if c.retryBudget == nil {
+		return 0, false
+	}
+	return c.retryBudget.exhaustedCount(), true
+}
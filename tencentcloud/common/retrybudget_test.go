@@ -0,0 +1,35 @@
+package common
+
+import (
+	"testing"
+)
+
+func TestRetryBudget(t *testing.T) {
+	budget := newRetryBudget(10, 5, 1)
+	for i := 0; i < 2; i++ {
+		if !budget.acquire() {
+			t.Fatalf("retry %d should be allowed, level %f", i, budget.level())
+		}
+	}
+	if budget.acquire() {
+		t.Fatalf("retry should be rejected when budget is empty")
+	}
+
+	for i := 0; i < 4; i++ {
+		budget.release()
+	}
+	if budget.acquire() {
+		t.Fatalf("retry should be rejected before budget refilled, level %f", budget.level())
+	}
+	budget.release()
+	if !budget.acquire() {
+		t.Fatalf("retry should be allowed after budget refilled")
+	}
+
+	for i := 0; i < 100; i++ {
+		budget.release()
+	}
+	if level := budget.level(); level != 10 {
+		t.Fatalf("unexpected budget level, expected %d, got %f", 10, level)
+	}
+}
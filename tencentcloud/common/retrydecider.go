@@ -0,0 +1,31 @@
+package common
+
+import (
+	"net"
+	"net/http"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// RetryDecider decides whether a failed attempt of request is retried, it replaces the built-in classification,
+// i.e. the ClientToken check, RetryableErrorCodes and the rate limit detection. For a network failure resp is nil
+// and err is the error of the transport, otherwise err is the error parsed from resp, whose body can be read.
+type RetryDecider func(request tchttp.Request, resp *http.Response, err error) bool
+
+// WithRetryDecider registers decider which is consulted for every failed attempt. The network failures
+// and the 5xx responses are retried with NetworkFailureMaxRetries and NetworkFailureRetryDuration,
+// the other errors with RateLimitExceededMaxRetries and RateLimitExceededRetryDuration.
+// The retries are still bounded by the context, MaxRetryElapsedTime and the retry budget.
+func (c *Client) WithRetryDecider(decider RetryDecider) *Client {
+	c.retryDecider = decider
+	return c
+}
+
+// retryNetworkError reports whether the network failure err is retried
+func (c *Client) retryNetworkError(request tchttp.Request, err error) bool {
+	if c.retryDecider != nil {
+		return c.retryDecider(request, nil, err)
+	}
+	netErr, ok := err.(net.Error)
+	return ok && (netErr.Timeout() || netErr.Temporary())
+}
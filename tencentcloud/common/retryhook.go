@@ -0,0 +1,92 @@
+package common
+
+import (
+	"context"
+	"time"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// RetryExhaustedEvent describes a call which finally failed because all its retries were exhausted
+type RetryExhaustedEvent struct {
+	// Attempts is the total number of http requests sent, including the first one
+	Attempts int
+	// Delay is the cumulative time spent waiting between the attempts
+	Delay time.Duration
+	// Err is the final error returned to the caller
+	Err error
+}
+
+// RetryHook is invoked right before the sleep of each retry, attempt is the number of the attempts sent so far,
+// reason is the error which causes the retry, e.g. a net.Error or a RequestLimitExceeded error,
+// and nextDelay is the time to sleep before the next attempt
+type RetryHook func(attempt int, reason error, nextDelay time.Duration)
+
+// retryStats records the retries of a single call
+type retryStats struct {
+	start    time.Time
+	attempts int
+	delay    time.Duration
+	reasons  []string
+	hook     RetryHook
+	// unsafeAction is the non-idempotent action whose retries are warned
+	unsafeAction string
+	// trace captures the timing of the attempts, it is nil unless Client.WithTimingCapture is enabled
+	trace *timingTrace
+}
+
+// retry records the reason of a retry and sleeps for duration before it,
+// the sleep is interrupted if ctx is done, then the retry fails with the context error
+func (s *retryStats) retry(ctx context.Context, reason string, cause error, duration time.Duration) {
+	if s.hook != nil {
+		s.hook(s.attempts, cause, duration)
+	}
+	warnNonIdempotentRetry(s.unsafeAction, reason)
+	s.reasons = append(s.reasons, reason)
+	s.delay += duration
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// withinRetryElapsedTime reports whether a retry after duration starts before MaxRetryElapsedTime elapses
+func (c *Client) withinRetryElapsedTime(stats *retryStats, duration time.Duration) bool {
+	max := c.profile.MaxRetryElapsedTime
+	return max <= 0 || time.Since(stats.start)+duration < max
+}
+
+// fill exposes the retries on the response, nothing is filled if no retry happened
+func (s *retryStats) fill(response tchttp.Response) {
+	if len(s.reasons) == 0 {
+		return
+	}
+	tchttp.SetRetryInfo(response, s.attempts, s.reasons)
+}
+
+// WithRetryHook registers hook which is invoked before every retry of the network failures,
+// the rate limited requests and the 5xx responses, e.g. to count the retries
+func (c *Client) WithRetryHook(hook RetryHook) *Client {
+	c.retryHook = hook
+	return c
+}
+
+// WithRetryExhaustedHook registers hook which is invoked exactly once for each call
+// that finally fails after exhausting its network failure or rate limit retries
+func (c *Client) WithRetryExhaustedHook(hook func(event RetryExhaustedEvent)) *Client {
+	c.retryExhaustedHook = hook
+	return c
+}
+
+func (c *Client) onRetryExhausted(stats *retryStats, err error) {
+	if c.retryExhaustedHook == nil {
+		return
+	}
+	c.retryExhaustedHook(RetryExhaustedEvent{
+		Attempts: stats.attempts,
+		Delay:    stats.delay,
+		Err:      err,
+	})
+}
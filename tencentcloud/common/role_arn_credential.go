@@ -45,10 +45,17 @@ func (c *RoleArnCredential) needRefresh() bool {
 	return false
 }
 
+func (c *RoleArnCredential) getExpiredTime() int64 {
+	return c.expiredTime
+}
+
 func (c *RoleArnCredential) refresh() {
 	newCre, err := c.source.GetCredential()
 	if err != nil {
 		log.Println(err)
+		return
 	}
+	oldExpiry := c.expiredTime
 	*c = *newCre.(*RoleArnCredential)
+	c.source.notify(oldExpiry, c.expiredTime)
 }
@@ -22,11 +22,15 @@ const (
 )
 
 type RoleArnProvider struct {
+	rotateNotifier
 	longSecretId    string
 	longSecretKey   string
 	roleArn         string
 	roleSessionName string
 	durationSeconds int64
+
+	// baseCredential signs the AssumeRole request instead of the long term secret if it is set
+	baseCredential CredentialIface
 }
 
 type stsRsp struct {
@@ -63,12 +67,15 @@ func (r *RoleArnProvider) GetCredential() (CredentialIface, error) {
 	if r.durationSeconds > 43200 || r.durationSeconds <= 0 {
 		return nil, tcerr.NewTencentCloudSDKError(creErr, "Assume Role durationSeconds should be in the range of 0~43200s", "")
 	}
-	credential := NewCredential(r.longSecretId, r.longSecretKey)
+	var credential CredentialIface = NewCredential(r.longSecretId, r.longSecretKey)
+	if r.baseCredential != nil {
+		credential = r.baseCredential
+	}
 	cpf := profile.NewClientProfile()
 	cpf.HttpProfile.Endpoint = endpoint
 	cpf.HttpProfile.ReqMethod = "POST"
 
-	client := NewCommonClient(credential, region, cpf)
+	client := new(Client).Init(region).WithCredential(credential).WithProfile(cpf)
 	request := tchttp.NewCommonRequest(service, version, action)
 
 	params := map[string]interface{}{
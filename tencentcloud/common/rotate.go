@@ -0,0 +1,34 @@
+package common
+
+import (
+	"sync"
+	"time"
+)
+
+// RotateFunc is called after a refreshing credential is rotated successfully,
+// with the expired time of the replaced credential and the one of the new credential
+type RotateFunc func(oldExpiry, newExpiry time.Time)
+
+// rotateNotifier holds the RotateFunc of a refreshing provider
+type rotateNotifier struct {
+	mu sync.Mutex
+	fn RotateFunc
+}
+
+// OnRotate registers fn to be called after each successful refresh of the credentials returned by the provider,
+// it can be registered before the first refresh. fn is called synchronously by the refresh, so it should return quickly.
+// A nil fn removes the registered one.
+func (n *rotateNotifier) OnRotate(fn RotateFunc) {
+	n.mu.Lock()
+	n.fn = fn
+	n.mu.Unlock()
+}
+
+func (n *rotateNotifier) notify(oldExpiry, newExpiry int64) {
+	n.mu.Lock()
+	fn := n.fn
+	n.mu.Unlock()
+	if fn != nil {
+		fn(time.Unix(oldExpiry, 0), time.Unix(newExpiry, 0))
+	}
+}
@@ -0,0 +1,29 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOnRotate(t *testing.T) {
+	provider := NewCvmRoleProvider("role")
+	// no callback registered
+	provider.notify(1, 2)
+
+	var oldExpiry, newExpiry time.Time
+	provider.OnRotate(func(o, n time.Time) {
+		oldExpiry, newExpiry = o, n
+	})
+	provider.notify(1600000000, 1600007200)
+	if oldExpiry.Unix() != 1600000000 || newExpiry.Unix() != 1600007200 {
+		t.Fatalf("unexpected expiry %v %v", oldExpiry, newExpiry)
+	}
+
+	assume := NewAssumeRoleProvider(NewCredential("id", "key"), "qcs::cam::uin/100:roleName/role", "session", 7200)
+	called := false
+	assume.OnRotate(func(time.Time, time.Time) { called = true })
+	assume.notify(1, 2)
+	if !called {
+		t.Fatalf("expected rotate callback of AssumeRoleProvider")
+	}
+}
@@ -13,6 +13,11 @@ func (ss sections) section(name string) *section {
 	return s
 }
 
+func (ss sections) has(name string) bool {
+	_, ok := ss.contains[name]
+	return ok
+}
+
 type section struct {
 	content map[string]*value
 }
@@ -7,8 +7,11 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
+	"fmt"
 	"sort"
+	"strings"
 
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
 	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
 )
 
@@ -27,24 +30,95 @@ func Sign(s, secretKey, method string) string {
 	return base64.StdEncoding.EncodeToString(hashed.Sum(nil))
 }
 
-func sha256hex(s string) string {
-	b := sha256.Sum256([]byte(s))
-	return hex.EncodeToString(b[:])
+// CryptoProvider supplies the hash primitives used by signature v3 and HmacSHA256 of signature v1,
+// implement it to route signing to a validated crypto module, e.g. for FIPS compliance.
+// HmacSHA1 of signature v1 always uses the go standard library, set ClientProfile.DisableSignatureV1
+// to make sure no request is signed by it.
+type CryptoProvider interface {
+	// Sha256 returns the SHA-256 digest of data
+	Sha256(data []byte) []byte
+	// HmacSha256 returns the HMAC-SHA256 of data keyed by key
+	HmacSha256(key, data []byte) []byte
 }
 
-func hmacsha256(s, key string) string {
-	hashed := hmac.New(sha256.New, []byte(key))
-	hashed.Write([]byte(s))
-	return string(hashed.Sum(nil))
+type stdCryptoProvider struct{}
+
+// DefaultCryptoProvider returns the CryptoProvider backed by the go standard library
+func DefaultCryptoProvider() CryptoProvider {
+	return stdCryptoProvider{}
+}
+
+func (stdCryptoProvider) Sha256(data []byte) []byte {
+	b := sha256.Sum256(data)
+	return b[:]
+}
+
+func (stdCryptoProvider) HmacSha256(key, data []byte) []byte {
+	hashed := hmac.New(sha256.New, key)
+	hashed.Write(data)
+	return hashed.Sum(nil)
+}
+
+func (c *Client) sha256hex(s string) string {
+	return hex.EncodeToString(c.cryptoProvider.Sha256([]byte(s)))
+}
+
+// Signer computes the HMAC-SHA256 chain of signature v3, which derives the signing key from the secret key
+// and signs the request with it. Implement it to keep the secret key in a HSM or KMS: in the first step,
+// key is "TC3" followed by the secret key of the credential, which can be a reference to the key held by the backend.
+type Signer interface {
+	// HmacSHA256 returns the HMAC-SHA256 of data keyed by key
+	HmacSHA256(key, data []byte) []byte
+}
+
+type stdSigner struct{}
+
+// DefaultSigner returns the Signer which computes the HMAC in process
+func DefaultSigner() Signer {
+	return stdSigner{}
+}
+
+func (stdSigner) HmacSHA256(key, data []byte) []byte {
+	return stdCryptoProvider{}.HmacSha256(key, data)
 }
 
-func signRequest(request tchttp.Request, credential CredentialIface, method string) (err error) {
+// WithSigner delegates the HMAC-SHA256 chain of signature v3, and HmacSHA256 of signature v1, to signer,
+// which takes precedence over the CryptoProvider, nil restores the default one.
+// HmacSHA1 of signature v1 is rejected with the error code ClientError.InvalidParameter once a signer is set,
+// since it can not be computed by the signer.
+func (c *Client) WithSigner(signer Signer) *Client {
+	c.signer = signer
+	c.resetSigningKeys()
+	return c
+}
+
+func (c *Client) hmacsha256(s, key string) string {
+	if c.signer != nil {
+		return string(c.signer.HmacSHA256([]byte(key), []byte(s)))
+	}
+	return string(c.cryptoProvider.HmacSha256([]byte(key), []byte(s)))
+}
+
+// v1HmacSHA256 returns the HMAC-SHA256 of signature v1, it is computed by the signer or the CryptoProvider like signature v3
+func (c *Client) v1HmacSHA256(key, data []byte) []byte {
+	if c.signer == nil && c.cryptoProvider == nil {
+		return stdCryptoProvider{}.HmacSha256(key, data)
+	}
+	return []byte(c.hmacsha256(string(data), string(key)))
+}
+
+func signRequest(request tchttp.Request, credential CredentialIface, method string, hmacSHA256 func(key, data []byte) []byte) (err error) {
 	if method != SHA256 {
 		method = SHA1
 	}
 	checkAuthParams(request, credential, method)
 	s := getStringToSign(request)
-	signature := Sign(s, credential.GetSecretKey(), method)
+	var signature string
+	if method == SHA256 && hmacSHA256 != nil {
+		signature = base64.StdEncoding.EncodeToString(hmacSHA256([]byte(credential.GetSecretKey()), []byte(s)))
+	} else {
+		signature = Sign(s, credential.GetSecretKey(), method)
+	}
 	request.GetParams()["Signature"] = signature
 	return
 }
@@ -88,3 +162,33 @@ func getStringToSign(request tchttp.Request) string {
 	buf.Truncate(buf.Len() - 1)
 	return buf.String()
 }
+
+// buildCanonicalHeaders returns the canonical headers and the signed headers of signature v3,
+// which are content-type, host and the extra headers, lowercased and sorted by name,
+// the value is trimmed and lowercased and the name of a missing extra header is reported by err
+func buildCanonicalHeaders(headers map[string]string, extra []string) (canonical, signed string, err error) {
+	values := make(map[string]string, len(headers))
+	for k, v := range headers {
+		values[strings.ToLower(k)] = v
+	}
+	names := []string{"content-type", "host"}
+	for _, name := range extra {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := values[name]; !ok {
+			msg := fmt.Sprintf("Signed header %s is not set", name)
+			return "", "", tcerr.NewTencentCloudSDKError("ClientError.InvalidParameter", msg, "")
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var buf strings.Builder
+	unique := names[:0]
+	for i, name := range names {
+		if i > 0 && name == names[i-1] {
+			continue
+		}
+		unique = append(unique, name)
+		buf.WriteString(name + ":" + strings.ToLower(strings.TrimSpace(values[name])) + "\n")
+	}
+	return buf.String(), strings.Join(unique, ";"), nil
+}
@@ -0,0 +1,74 @@
+package common
+
+import "sync"
+
+// signingKeyScope identifies a signing key of signature v3, the secret key is part of it,
+// so that the key derived from a rotated credential is never reused
+type signingKeyScope struct {
+	date      string
+	service   string
+	secretId  string
+	secretKey string
+}
+
+// signingKeyCache caches the signing keys of signature v3, which are derived from the secret key
+// by three HMACs per date and service, so the derivation is done once a day per service
+type signingKeyCache struct {
+	mu   sync.Mutex
+	keys map[signingKeyScope]string
+}
+
+func newSigningKeyCache() *signingKeyCache {
+	return &signingKeyCache{keys: make(map[signingKeyScope]string)}
+}
+
+// WithSigningKeyCache enables or disables the cache of the signing keys of signature v3, which is enabled
+// by default. The key derived from the secret key is reused by the requests of the same date and service,
+// which saves three HMACs per request, it is derived again once the date changes or the credential rotates.
+func (c *Client) WithSigningKeyCache(enabled bool) *Client {
+	if enabled {
+		c.signingKeys = newSigningKeyCache()
+	} else {
+		c.signingKeys = nil
+	}
+	return c
+}
+
+// resetSigningKeys drops the cached signing keys, which are derived by the previous signer or crypto provider
+func (c *Client) resetSigningKeys() {
+	if c.signingKeys != nil {
+		c.signingKeys = newSigningKeyCache()
+	}
+}
+
+// signingKey returns the signing key of signature v3 for date and service
+func (c *Client) signingKey(date, service, secretId, secretKey string) string {
+	if c.signingKeys == nil {
+		return c.deriveSigningKey(date, service, secretKey)
+	}
+	scope := signingKeyScope{date: date, service: service, secretId: secretId, secretKey: secretKey}
+	c.signingKeys.mu.Lock()
+	key, ok := c.signingKeys.keys[scope]
+	c.signingKeys.mu.Unlock()
+	if ok {
+		return key
+	}
+
+	key = c.deriveSigningKey(date, service, secretKey)
+	c.signingKeys.mu.Lock()
+	defer c.signingKeys.mu.Unlock()
+	// the keys of the previous dates and credentials are never used again
+	for s := range c.signingKeys.keys {
+		if s.date != date || s.secretId != secretId || s.secretKey != secretKey {
+			delete(c.signingKeys.keys, s)
+		}
+	}
+	c.signingKeys.keys[scope] = key
+	return key
+}
+
+func (c *Client) deriveSigningKey(date, service, secretKey string) string {
+	secretDate := c.hmacsha256(date, "TC3"+secretKey)
+	secretService := c.hmacsha256(service, secretDate)
+	return c.hmacsha256("tc3_request", secretService)
+}
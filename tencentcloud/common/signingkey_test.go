@@ -0,0 +1,75 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/regions"
+)
+
+type countingSigner struct {
+	calls int
+}
+
+func (s *countingSigner) HmacSHA256(key, data []byte) []byte {
+	s.calls++
+	return DefaultSigner().HmacSHA256(key, data)
+}
+
+func TestSigningKeyCache(t *testing.T) {
+	client := NewCommonClient(NewCredential("", ""), regions.Guangzhou, profile.NewClientProfile())
+	signer := &countingSigner{}
+	client.WithSigner(signer)
+
+	expected := client.deriveSigningKey("2020-09-13", "cvm", "key")
+	signer.calls = 0
+	for i := 0; i < 3; i++ {
+		if key := client.signingKey("2020-09-13", "cvm", "id", "key"); key != expected {
+			t.Fatalf("unexpected signing key")
+		}
+	}
+	if signer.calls != 3 {
+		t.Fatalf("the signing key should be derived once, %d hmacs computed", signer.calls)
+	}
+
+	client.signingKey("2020-09-13", "ccc", "id", "key")
+	if signer.calls != 6 || len(client.signingKeys.keys) != 2 {
+		t.Fatalf("unexpected %d hmacs computed, %d keys cached", signer.calls, len(client.signingKeys.keys))
+	}
+	// the keys of the previous date and credential are dropped
+	client.signingKey("2020-09-14", "cvm", "id", "key")
+	if signer.calls != 9 || len(client.signingKeys.keys) != 1 {
+		t.Fatalf("unexpected %d hmacs computed, %d keys cached", signer.calls, len(client.signingKeys.keys))
+	}
+	if key := client.signingKey("2020-09-14", "cvm", "id", "rotated"); key == client.deriveSigningKey("2020-09-14", "cvm", "key") {
+		t.Fatalf("the signing key of the rotated credential should not be reused")
+	}
+
+	client.WithSigningKeyCache(false)
+	signer.calls = 0
+	client.signingKey("2020-09-13", "cvm", "id", "key")
+	client.signingKey("2020-09-13", "cvm", "id", "key")
+	if signer.calls != 6 {
+		t.Fatalf("unexpected %d hmacs computed without cache", signer.calls)
+	}
+}
+
+// benchmarkSignatureV3 signs a string with the signing key of signature v3, as a request does
+func benchmarkSignatureV3(b *testing.B, cached bool) {
+	client := NewCommonClient(NewCredential("", ""), regions.Guangzhou, profile.NewClientProfile())
+	client.WithSigningKeyCache(cached)
+	string2sign := "TC3-HMAC-SHA256\n1600000000\n2020-09-13/cvm/tc3_request\n" + client.sha256hex("canonical request")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		key := client.signingKey("2020-09-13", "cvm", "AKID", "secret")
+		client.hmacsha256(string2sign, key)
+	}
+}
+
+func BenchmarkSignatureV3Cached(b *testing.B) {
+	benchmarkSignatureV3(b, true)
+}
+
+func BenchmarkSignatureV3Uncached(b *testing.B) {
+	benchmarkSignatureV3(b, false)
+}
@@ -0,0 +1,151 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// errNotCacheable is returned for the responses which do not keep the raw body, they are never shared nor cached
+var errNotCacheable = errors.New("response is not cacheable")
+
+// sharedCall is a call in flight, the identical calls made meanwhile wait for its result
+type sharedCall struct {
+	done   chan struct{}
+	result *sharedResult
+	err    error
+}
+
+// callGroup coalesces the identical calls in flight, it is used by WithSingleFlight and WithReadCache.
+// The call is made in the goroutine of the first caller, since it writes the response of that caller.
+type callGroup struct {
+	mu    sync.Mutex
+	calls map[string]*sharedCall
+}
+
+// do calls fn for key unless an identical call is in flight, whose result is then returned, leader reports
+// whether fn is called. A waiting caller returns the error of its own ctx once ctx is done, and calls fn itself
+// if the call in flight fails because the context of its caller is done, since the error is not its own.
+func (g *callGroup) do(ctx context.Context, key string, fn func() (*sharedResult, error)) (result *sharedResult, err error, leader bool) {
+	for {
+		g.mu.Lock()
+		if g.calls == nil {
+			g.calls = make(map[string]*sharedCall)
+		}
+		if call, ok := g.calls[key]; ok {
+			g.mu.Unlock()
+			select {
+			case <-call.done:
+			case <-ctx.Done():
+				return nil, ctx.Err(), false
+			}
+			if isContextError(call.err) && ctx.Err() == nil {
+				continue
+			}
+			return call.result, call.err, false
+		}
+		call := &sharedCall{done: make(chan struct{})}
+		g.calls[key] = call
+		g.mu.Unlock()
+
+		call.result, call.err = fn()
+
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+		return call.result, call.err, true
+	}
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// sharedResult is the response of a call shared by the identical calls in flight
+type sharedResult struct {
+	body   []byte
+	header http.Header
+}
+
+// parse parses a copy of the result into response, so it shares nothing with the other responses
+func (r *sharedResult) parse(response tchttp.Response) error {
+	header := http.Header{}
+	for k, values := range r.header {
+		header[k] = append([]string(nil), values...)
+	}
+	return tchttp.ParseFromHttpResponse(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewReader(append([]byte(nil), r.body...))),
+	}, response)
+}
+
+// sendShared sends request with send through group, unless an identical call of key is in flight,
+// whose successful response is then parsed into response. It returns the result of the call if it is sent
+// by this caller and succeeds, e.g. to be cached, the result is nil if the response keeps no raw body.
+func sendShared(group *callGroup, key string, request tchttp.Request, response tchttp.Response, send func(tchttp.Request, tchttp.Response) error) (*sharedResult, error) {
+	result, err, leader := group.do(request.GetContext(), key, func() (*sharedResult, error) {
+		if err := send(request, response); err != nil {
+			return nil, err
+		}
+		raw, ok := response.(interface{ GetRawBody() []byte })
+		if !ok || raw.GetRawBody() == nil {
+			return nil, errNotCacheable
+		}
+		// the result keeps its own copy, so it shares nothing with the response
+		result := &sharedResult{body: append([]byte(nil), raw.GetRawBody()...)}
+		if headers, ok := response.(interface{ GetHeaders() http.Header }); ok {
+			result.header = headers.GetHeaders()
+		}
+		return result, nil
+	})
+	if leader {
+		if err == errNotCacheable {
+			return nil, nil
+		}
+		return result, err
+	}
+	if err == errNotCacheable {
+		return nil, send(request, response)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, result.parse(response)
+}
+
+// WithSingleFlight makes the identical calls of the read actions in flight share one request,
+// the first call sends the request and the others wait for it and get a copy of its result, errors included.
+// A waiting call stops waiting once its own context is done, and sends its own request if the first call
+// fails because the context of the first call is done.
+// Calls are identical if they have the same domain, version, action, region and parameters,
+// the read actions are those accepted by IsReadAction, so the distinct mutations are never collapsed.
+// Unlike WithReadCache and WithResponseCache, nothing is kept once the call returns.
+//
+// It is disabled by default.
+func (c *Client) WithSingleFlight(enabled bool) *Client {
+	if enabled {
+		c.singleFlight = &callGroup{}
+	} else {
+		c.singleFlight = nil
+	}
+	return c
+}
+
+// sendWithSingleFlight returns a send func which shares the call of request with the identical calls in flight
+func (c *Client) sendWithSingleFlight(group *callGroup, send func(tchttp.Request, tchttp.Response) error) func(tchttp.Request, tchttp.Response) error {
+	return func(request tchttp.Request, response tchttp.Response) error {
+		key, err := responseCacheKey(request)
+		if err != nil {
+			return send(request, response)
+		}
+		_, err = sendShared(group, key, request, response, send)
+		return err
+	}
+}
@@ -0,0 +1,32 @@
+package common
+
+import (
+	"context"
+	"net/http"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// streamingResponse captures the live http response handed over by SendStreaming
+type streamingResponse struct {
+	*tchttp.StreamResponse
+	httpResponse *http.Response
+}
+
+// SendStreaming signs and sends request like Send, but returns the live http response of the server
+// without reading nor parsing its body, e.g. to read the server-sent events or the long-poll results
+// of a real-time API incrementally. The caller must close the body of the response.
+//
+// A non 200 response is parsed into the returned error as usual and its body is closed, but the API error
+// carried by a 200 response is not detected, nor is the request retried on it, the caller should check it.
+// The request is never served by the caches. The ReqTimeout of HttpProfile covers reading the body as well,
+// so a long-lived stream should be sent with a long deadline on the context of request, which replaces it,
+// see SendWithContext. The concurrency slot of WithMaxConcurrent is released once the response is returned.
+func (c *Client) SendStreaming(request tchttp.Request) (*http.Response, error) {
+	request.SetContext(context.WithValue(request.GetContext(), bypassCacheKey{}, true))
+	response := &streamingResponse{StreamResponse: tchttp.NewStreamResponse()}
+	if err := c.Send(request, response); err != nil {
+		return nil, err
+	}
+	return response.httpResponse, nil
+}
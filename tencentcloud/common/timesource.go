@@ -0,0 +1,36 @@
+package common
+
+import (
+	"strconv"
+	"time"
+)
+
+// WithTimeSource replaces the clock which the timestamps of the requests are taken from, e.g. a fixed time
+// so that the signatures are deterministic in tests, nil restores time.Now. The clock skew detected
+// from the server is still added to it.
+func (c *Client) WithTimeSource(source func() time.Time) *Client {
+	c.timeSource = source
+	return c
+}
+
+// WithNonceFunc replaces the random Nonce common parameter, which is signed by signature v1,
+// e.g. with a constant for deterministic signatures in tests, nil restores the random one.
+// The nonce must be a positive integer, never use a constant in production since it prevents replay attacks.
+func (c *Client) WithNonceFunc(f func() int) *Client {
+	c.nonceFunc = f
+	return c
+}
+
+func (c *Client) timeNow() time.Time {
+	if c.timeSource != nil {
+		return c.timeSource()
+	}
+	return time.Now()
+}
+
+// completeNonce overrides the random Nonce set by CompleteCommonParams if a nonce func is set
+func (c *Client) completeNonce(params map[string]string) {
+	if c.nonceFunc != nil {
+		params["Nonce"] = strconv.Itoa(c.nonceFunc())
+	}
+}
@@ -0,0 +1,67 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+)
+
+// TimestampFunc formats the time when a request is sent as the Timestamp common parameter,
+// which is also the X-TC-Timestamp header of signature v3
+type TimestampFunc func(now time.Time) string
+
+// SecondTimestamp formats now as Unix seconds, which is required by Tencent Cloud API and used by default
+func SecondTimestamp(now time.Time) string {
+	return strconv.FormatInt(now.Unix(), 10)
+}
+
+// MillisecondTimestamp formats now as Unix milliseconds, for the services which expect millisecond timestamps
+func MillisecondTimestamp(now time.Time) string {
+	return strconv.FormatInt(now.UnixNano()/int64(time.Millisecond), 10)
+}
+
+// WithTimestampFunc overrides how the timestamp of requests is formatted, nil restores SecondTimestamp.
+// The timestamp must be a Unix timestamp in decimal, in seconds or milliseconds for signature v3,
+// since the date of the credential scope is derived from it. Requests fail with ClientError.InvalidTimestamp otherwise.
+func (c *Client) WithTimestampFunc(f TimestampFunc) *Client {
+	c.timestampFunc = f
+	return c
+}
+
+// completeTimestamp sets the Timestamp common parameter with the time corrected by the clock skew
+func (c *Client) completeTimestamp(params map[string]string) error {
+	if c.timestampFunc == nil {
+		params["Timestamp"] = SecondTimestamp(c.now())
+		return nil
+	}
+	timestamp := c.timestampFunc(c.now())
+	if _, err := parseTimestamp(timestamp, c.signMethod != "HmacSHA1" && c.signMethod != "HmacSHA256"); err != nil {
+		return err
+	}
+	params["Timestamp"] = timestamp
+	return nil
+}
+
+// parseTimestamp parses the Unix timestamp in seconds or milliseconds,
+// the precision is only checked for signature v3 which derives the date from it
+func parseTimestamp(timestamp string, v3 bool) (time.Time, error) {
+	invalid := func(reason string) error {
+		msg := fmt.Sprintf("Invalid timestamp %q, %s", timestamp, reason)
+		return errors.NewTencentCloudSDKError("ClientError.InvalidTimestamp", msg, "")
+	}
+	value, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || value < 0 {
+		return time.Time{}, invalid("it must be a Unix timestamp in decimal")
+	}
+	switch {
+	case len(timestamp) <= 10:
+		return time.Unix(value, 0), nil
+	case len(timestamp) <= 13:
+		return time.Unix(0, value*int64(time.Millisecond)), nil
+	case v3:
+		return time.Time{}, invalid("signature v3 requires a timestamp in seconds or milliseconds")
+	}
+	return time.Time{}, nil
+}
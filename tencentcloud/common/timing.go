@@ -0,0 +1,111 @@
+package common
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// WithTimingCapture captures the timing of every call, i.e. the signing, the DNS lookup, the connect,
+// the TLS handshake and the time to first byte, which is returned by GetTiming of the response,
+// e.g. to tell the slow network from the slow server. Nothing is traced if it is disabled.
+func (c *Client) WithTimingCapture(enabled bool) *Client {
+	c.timingCapture = enabled
+	return c
+}
+
+// timingTrace captures the timing of a call through httptrace,
+// the callbacks may be invoked concurrently, e.g. by the parallel dials of a dual-stack host
+type timingTrace struct {
+	mu     sync.Mutex
+	timing tchttp.Timing
+
+	start        time.Time
+	attemptStart time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+}
+
+// newTimingTrace starts the trace of a call if the timing is captured, otherwise it returns nil
+func (c *Client) newTimingTrace() *timingTrace {
+	if !c.timingCapture {
+		return nil
+	}
+	return &timingTrace{start: time.Now()}
+}
+
+// signed records the end of the signing
+func (t *timingTrace) signed() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.timing.Signing = time.Since(t.start)
+	t.mu.Unlock()
+}
+
+// trace returns req which reports the timing of its attempt, the phases of the previous attempt are reset
+func (t *timingTrace) trace(req *http.Request) *http.Request {
+	if t == nil {
+		return req
+	}
+	t.mu.Lock()
+	t.timing = tchttp.Timing{Signing: t.timing.Signing}
+	t.attemptStart = time.Now()
+	t.mu.Unlock()
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.record(func() { t.timing.ReusedConnection = info.Reused })
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.record(func() { t.dnsStart = time.Now() })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.record(func() { t.timing.DNSLookup = time.Since(t.dnsStart) })
+		},
+		ConnectStart: func(string, string) {
+			t.record(func() {
+				if t.connectStart.Before(t.attemptStart) {
+					t.connectStart = time.Now()
+				}
+			})
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				t.record(func() { t.timing.Connect = time.Since(t.connectStart) })
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.record(func() { t.tlsStart = time.Now() })
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.record(func() { t.timing.TLSHandshake = time.Since(t.tlsStart) })
+		},
+		GotFirstResponseByte: func() {
+			t.record(func() { t.timing.TimeToFirstByte = time.Since(t.attemptStart) })
+		},
+	}))
+}
+
+func (t *timingTrace) record(f func()) {
+	t.mu.Lock()
+	f()
+	t.mu.Unlock()
+}
+
+// fill exposes the timing on the response
+func (t *timingTrace) fill(response tchttp.Response) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	timing := t.timing
+	t.mu.Unlock()
+	timing.Total = time.Since(t.start)
+	tchttp.SetTiming(response, &timing)
+}
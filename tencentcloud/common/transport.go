@@ -0,0 +1,135 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
+)
+
+// newTransport builds the transport of the client from the settings of httpProfile, it returns nil
+// if none of them is set, so that the client sends through http.DefaultTransport, even a replaced one
+func newTransport(httpProfile *profile.HttpProfile) http.RoundTripper {
+	if !hasTransportSettings(httpProfile) {
+		return nil
+	}
+	transport := defaultTransport()
+	transport.Proxy = proxyFunc(httpProfile.ProxyURL)
+	if httpProfile.TLSConfig != nil {
+		transport.TLSClientConfig = httpProfile.TLSConfig.Clone()
+	}
+	if httpProfile.ConnectAddress != "" {
+		// the connect address replaces the address of the proxy as well
+		transport.Proxy = nil
+	}
+	if httpProfile.DialPreference != "" || httpProfile.ConnectAddress != "" || httpProfile.DialTimeout > 0 || httpProfile.KeepAlivePeriod != 0 {
+		transport.DialContext = dialContext(httpProfile.DialPreference, httpProfile.ConnectAddress, httpProfile.DialTimeout, httpProfile.KeepAlivePeriod)
+	}
+	transport.DisableKeepAlives = httpProfile.DisableKeepAlives
+	if httpProfile.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = httpProfile.TLSHandshakeTimeout
+	}
+	if httpProfile.ResponseHeaderTimeout > 0 {
+		transport.ResponseHeaderTimeout = httpProfile.ResponseHeaderTimeout
+	}
+	return transport
+}
+
+// hasTransportSettings reports whether any field of httpProfile applied by newTransport is set
+func hasTransportSettings(httpProfile *profile.HttpProfile) bool {
+	return httpProfile.ProxyURL != "" || httpProfile.TLSConfig != nil || httpProfile.DialPreference != "" ||
+		httpProfile.ConnectAddress != "" || httpProfile.DialTimeout > 0 || httpProfile.TLSHandshakeTimeout > 0 ||
+		httpProfile.ResponseHeaderTimeout > 0 || httpProfile.DisableKeepAlives || httpProfile.KeepAlivePeriod != 0
+}
+
+// defaultTransport returns a copy of http.DefaultTransport, or a transport with the same settings
+// if it is replaced by one which is not a *http.Transport, e.g. by a http mock
+func defaultTransport() *http.Transport {
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		return transport.Clone()
+	}
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// dialContext returns the dial func of the transport which connects through the network preference,
+// to connectAddress instead of the address of the request if it is not empty.
+// The dialer has the same settings as the one of http.DefaultTransport except the timeout if it is positive
+// and the TCP keep-alive period if it is not zero.
+func dialContext(preference, connectAddress string, timeout, keepAlive time.Duration) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if connectAddress != "" {
+		if _, _, err := net.SplitHostPort(connectAddress); err != nil {
+			msg := fmt.Sprintf("Invalid connect address %q because %s", connectAddress, err)
+			err := errors.NewTencentCloudSDKErrorWithCause("ClientError.InvalidConnectAddress", msg, "", err)
+			return func(context.Context, string, string) (net.Conn, error) {
+				return nil, err
+			}
+		}
+	}
+	switch preference {
+	case "":
+	case "tcp", "tcp4", "tcp6":
+	default:
+		// the error is reported by every request sent through the transport
+		msg := fmt.Sprintf("Invalid dial preference %q, valid choices: tcp, tcp4, tcp6", preference)
+		err := errors.NewTencentCloudSDKError("ClientError.InvalidDialPreference", msg, "")
+		return func(context.Context, string, string) (net.Conn, error) {
+			return nil, err
+		}
+	}
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if timeout > 0 {
+		dialer.Timeout = timeout
+	}
+	if keepAlive != 0 {
+		dialer.KeepAlive = keepAlive
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if preference != "" {
+			network = preference
+		}
+		if connectAddress != "" {
+			addr = connectAddress
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+}
+
+// proxyFunc returns the proxy func of the transport, the proxy specified by proxyURL
+// takes precedence over the one from the environment variables
+func proxyFunc(proxyURL string) func(*http.Request) (*url.URL, error) {
+	if proxyURL == "" {
+		return http.ProxyFromEnvironment
+	}
+	u, err := url.Parse(proxyURL)
+	if err == nil && u.Host == "" {
+		err = fmt.Errorf("missing host")
+	}
+	if err != nil {
+		// the error is reported by every request sent through the transport
+		msg := fmt.Sprintf("Invalid proxy url %q because %s", proxyURL, err)
+		err = errors.NewTencentCloudSDKErrorWithCause("ClientError.InvalidProxyURL", msg, "", err)
+		return func(*http.Request) (*url.URL, error) {
+			return nil, err
+		}
+	}
+	return http.ProxyURL(u)
+}
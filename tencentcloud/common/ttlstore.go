@@ -0,0 +1,64 @@
+package common
+
+import (
+	"time"
+)
+
+// ttlStoreMaxEntries bounds the memory used by a ttlStore, each entry holds the raw body of one response,
+// so a store takes at most ttlStoreMaxEntries * (max response size) bytes.
+const ttlStoreMaxEntries = 1024
+
+type ttlEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+// ttlStore holds the bodies until they expire, it is shared by the read cache and the memory response cache.
+// When it is full, the expired bodies are purged, then the one which expires first is evicted.
+// It is not safe for concurrent use, the caller must hold its own lock.
+type ttlStore struct {
+	entries map[string]ttlEntry
+}
+
+func newTTLStore() *ttlStore {
+	return &ttlStore{entries: make(map[string]ttlEntry)}
+}
+
+// get returns the body of key, ok is false if it is missing or expired
+func (s *ttlStore) get(key string) (body []byte, ok bool) {
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !time.Now().Before(entry.expires) {
+		delete(s.entries, key)
+		return nil, false
+	}
+	return entry.body, true
+}
+
+// set stores body for key, it expires after ttl
+func (s *ttlStore) set(key string, body []byte, ttl time.Duration) {
+	now := time.Now()
+	if _, ok := s.entries[key]; !ok && len(s.entries) >= ttlStoreMaxEntries {
+		var oldest string
+		var oldestExpires time.Time
+		for k, entry := range s.entries {
+			if !now.Before(entry.expires) {
+				delete(s.entries, k)
+				continue
+			}
+			if oldest == "" || entry.expires.Before(oldestExpires) {
+				oldest, oldestExpires = k, entry.expires
+			}
+		}
+		if len(s.entries) >= ttlStoreMaxEntries {
+			delete(s.entries, oldest)
+		}
+	}
+	s.entries[key] = ttlEntry{body: body, expires: now.Add(ttl)}
+}
+
+func (s *ttlStore) delete(key string) {
+	delete(s.entries, key)
+}
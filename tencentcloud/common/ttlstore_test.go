@@ -0,0 +1,34 @@
+package common
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTTLStore(t *testing.T) {
+	store := newTTLStore()
+	store.set("expired", []byte("a"), -time.Second)
+	if _, ok := store.get("expired"); ok || len(store.entries) != 0 {
+		t.Fatalf("expired body should be purged")
+	}
+
+	store.set("first", []byte("b"), time.Minute)
+	for i := 1; i < ttlStoreMaxEntries; i++ {
+		store.set(strconv.Itoa(i), []byte("c"), time.Hour)
+	}
+	// the one which expires first is evicted when it is full
+	store.set("last", []byte("d"), time.Hour)
+	if _, ok := store.get("first"); ok || len(store.entries) != ttlStoreMaxEntries {
+		t.Fatalf("unexpected %d bodies", len(store.entries))
+	}
+	// replacing a key evicts none
+	store.set("last", []byte("e"), time.Hour)
+	if body, ok := store.get("last"); !ok || string(body) != "e" || len(store.entries) != ttlStoreMaxEntries {
+		t.Fatalf("unexpected body %s of %d bodies", body, len(store.entries))
+	}
+	store.delete("last")
+	if _, ok := store.get("last"); ok {
+		t.Fatalf("deleted body should be missing")
+	}
+}
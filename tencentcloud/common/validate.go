@@ -0,0 +1,207 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	phonePattern = regexp.MustCompile(`^\+?[0-9]{5,20}$`)
+)
+
+// FieldError is a field of a request which violates a rule of its validate tag
+type FieldError struct {
+	// Field is the path of the field as sent to the API, e.g. Staffs.0.Mail
+	Field string
+	// Rule is the violated rule, e.g. required or maxlen=10
+	Rule    string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Message)
+}
+
+// ValidationError collects the errors of all the fields of a request which fail the validation
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "invalid request: " + strings.Join(messages, "; ")
+}
+
+// ValidateRequest validates the fields of request against their validate tags, e.g.
+//
+//	Staffs []*SeatUserInfo `name:"Staffs" validate:"required,maxlen=10"`
+//
+// The rules are separated by commas, valid choices:
+//
+//	required     the field is set, and not empty if it is a slice
+//	minlen=n     the string has at least n characters, or the slice has at least n elements
+//	maxlen=n     the string has at most n characters, or the slice has at most n elements
+//	min=n        the number is not less than n
+//	max=n        the number is not greater than n
+//	enum=a|b     the value formatted as a string is one of a and b
+//	format=f     the string is an email or a phone, e.g. +8613800000000
+//
+// The rules other than required are skipped for the field not set. The structs nested in the fields,
+// including the elements of the slices, are validated as well. It returns nil if all the fields are valid,
+// otherwise a *ValidationError. Client.Send validates every request before sending it.
+func ValidateRequest(request interface{}) error {
+	value := reflect.ValueOf(request)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	var errs []*FieldError
+	validateStruct(value, "", &errs)
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// validateStruct validates the fields of value which are sent to the API, i.e. those with a name tag
+func validateStruct(value reflect.Value, prefix string, errs *[]*FieldError) {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		name, ok := valueType.Field(i).Tag.Lookup("name")
+		if !ok {
+			continue
+		}
+		field := value.Field(i)
+		path := prefix + name
+		if tag := valueType.Field(i).Tag.Get("validate"); tag != "" {
+			for _, rule := range strings.Split(tag, ",") {
+				if msg := checkRule(field, rule); msg != "" {
+					*errs = append(*errs, &FieldError{Field: path, Rule: rule, Message: msg})
+				}
+			}
+		}
+		validateNested(field, path, errs)
+	}
+}
+
+// validateNested validates the structs in field, which is a struct, a pointer or a slice of them
+func validateNested(field reflect.Value, path string, errs *[]*FieldError) {
+	switch field.Kind() {
+	case reflect.Ptr:
+		if !field.IsNil() {
+			validateNested(field.Elem(), path, errs)
+		}
+	case reflect.Struct:
+		validateStruct(field, path+".", errs)
+	case reflect.Slice:
+		for j := 0; j < field.Len(); j++ {
+			validateNested(field.Index(j), path+"."+strconv.Itoa(j), errs)
+		}
+	}
+}
+
+// checkRule returns the message of the violation of rule by field, it is empty if field is valid
+func checkRule(field reflect.Value, rule string) string {
+	rule = strings.TrimSpace(rule)
+	key, arg := rule, ""
+	if idx := strings.Index(rule, "="); idx >= 0 {
+		key, arg = rule[:idx], rule[idx+1:]
+	}
+	if key == "required" {
+		if (field.Kind() == reflect.Ptr && field.IsNil()) || (field.Kind() == reflect.Slice && field.Len() == 0) {
+			return "is required"
+		}
+		return ""
+	}
+	for field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return ""
+		}
+		field = field.Elem()
+	}
+
+	switch key {
+	case "minlen", "maxlen":
+		limit, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Sprintf("has an invalid rule %s", rule)
+		}
+		var length int
+		switch field.Kind() {
+		case reflect.String:
+			length = utf8.RuneCountInString(field.String())
+		case reflect.Slice:
+			length = field.Len()
+		default:
+			return fmt.Sprintf("has an invalid rule %s for %s", rule, field.Kind())
+		}
+		if key == "minlen" && length < limit {
+			return fmt.Sprintf("must have at least %d characters or elements, got %d", limit, length)
+		}
+		if key == "maxlen" && length > limit {
+			return fmt.Sprintf("must have at most %d characters or elements, got %d", limit, length)
+		}
+	case "min", "max":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Sprintf("has an invalid rule %s", rule)
+		}
+		var number float64
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			number = float64(field.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			number = float64(field.Uint())
+		case reflect.Float32, reflect.Float64:
+			number = field.Float()
+		default:
+			return fmt.Sprintf("has an invalid rule %s for %s", rule, field.Kind())
+		}
+		if key == "min" && number < limit {
+			return fmt.Sprintf("must not be less than %s, got %v", arg, number)
+		}
+		if key == "max" && number > limit {
+			return fmt.Sprintf("must not be greater than %s, got %v", arg, number)
+		}
+	case "enum":
+		actual := fmt.Sprint(field.Interface())
+		for _, allowed := range strings.Split(arg, "|") {
+			if actual == allowed {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of %s, got %s", strings.Replace(arg, "|", ", ", -1), actual)
+	case "format":
+		if field.Kind() != reflect.String {
+			return fmt.Sprintf("has an invalid rule %s for %s", rule, field.Kind())
+		}
+		var pattern *regexp.Regexp
+		switch arg {
+		case "email":
+			pattern = emailPattern
+		case "phone":
+			pattern = phonePattern
+		default:
+			return fmt.Sprintf("has an unknown format %s", arg)
+		}
+		if !pattern.MatchString(field.String()) {
+			return fmt.Sprintf("must be a valid %s, got %q", arg, field.String())
+		}
+	default:
+		return fmt.Sprintf("has an unknown rule %s", rule)
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package common
+
+import (
+	"errors"
+	"testing"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+type validatedStaff struct {
+	Mail  *string `json:"Mail,omitempty" name:"Mail" validate:"required,format=email"`
+	Phone *string `json:"Phone,omitempty" name:"Phone" validate:"format=phone"`
+	Name  *string `json:"Name,omitempty" name:"Name" validate:"minlen=2,maxlen=4"`
+}
+
+type validatedRequest struct {
+	*tchttp.BaseRequest
+	SdkAppId *int64            `json:"SdkAppId,omitempty" name:"SdkAppId" validate:"required,min=1"`
+	Order    *int64            `json:"Order,omitempty" name:"Order" validate:"enum=1|2"`
+	Staffs   []*validatedStaff `json:"Staffs,omitempty" name:"Staffs" validate:"required,maxlen=2"`
+}
+
+func TestValidateRequest(t *testing.T) {
+	valid := func() *validatedRequest {
+		return &validatedRequest{
+			BaseRequest: &tchttp.BaseRequest{},
+			SdkAppId:    Int64Ptr(1400000000),
+			Order:       Int64Ptr(2),
+			Staffs:      []*validatedStaff{{Mail: StringPtr("a@example.com"), Phone: StringPtr("008613800000000"), Name: StringPtr("张三")}},
+		}
+	}
+	if err := ValidateRequest(valid()); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	cases := []struct {
+		mutate func(r *validatedRequest)
+		field  string
+		rule   string
+	}{
+		{func(r *validatedRequest) { r.SdkAppId = nil }, "SdkAppId", "required"},
+		{func(r *validatedRequest) { r.SdkAppId = Int64Ptr(0) }, "SdkAppId", "min=1"},
+		{func(r *validatedRequest) { r.Order = Int64Ptr(3) }, "Order", "enum=1|2"},
+		{func(r *validatedRequest) { r.Staffs = nil }, "Staffs", "required"},
+		{func(r *validatedRequest) { r.Staffs = append(r.Staffs, r.Staffs[0], r.Staffs[0]) }, "Staffs", "maxlen=2"},
+		{func(r *validatedRequest) { r.Staffs[0].Mail = StringPtr("a.example.com") }, "Staffs.0.Mail", "format=email"},
+		{func(r *validatedRequest) { r.Staffs[0].Phone = StringPtr("138-0000") }, "Staffs.0.Phone", "format=phone"},
+		{func(r *validatedRequest) { r.Staffs[0].Name = StringPtr("张") }, "Staffs.0.Name", "minlen=2"},
+	}
+	for _, c := range cases {
+		request := valid()
+		c.mutate(request)
+		err := ValidateRequest(request)
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 {
+			t.Fatalf("%s: unexpected error %+v", c.field, err)
+		}
+		if fieldErr := validationErr.Errors[0]; fieldErr.Field != c.field || fieldErr.Rule != c.rule {
+			t.Fatalf("%s: unexpected field error %+v", c.field, fieldErr)
+		}
+	}
+}
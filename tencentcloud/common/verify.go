@@ -0,0 +1,85 @@
+package common
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// VerifyFailure is the kind of the failure reported by Client.Verify
+type VerifyFailure int
+
+const (
+	// VerifyAuthFailure means the credential is rejected, e.g. the secret id does not exist or the signature mismatches
+	VerifyAuthFailure VerifyFailure = iota + 1
+	// VerifyNetworkFailure means the server can not be reached, or ctx is done before it responds
+	VerifyNetworkFailure
+	// VerifyOtherFailure means the others, e.g. a closed client or an unexpected response
+	VerifyOtherFailure
+)
+
+func (f VerifyFailure) String() string {
+	switch f {
+	case VerifyAuthFailure:
+		return "AuthFailure"
+	case VerifyNetworkFailure:
+		return "NetworkFailure"
+	case VerifyOtherFailure:
+		return "OtherFailure"
+	}
+	return "Unknown"
+}
+
+// VerifyError is returned by Client.Verify, Err is the error of the verification request
+type VerifyError struct {
+	Failure VerifyFailure
+	Err     error
+}
+
+func (e *VerifyError) Error() string {
+	return "verify failed, " + e.Failure.String() + ": " + e.Err.Error()
+}
+
+func (e *VerifyError) Unwrap() error {
+	return e.Err
+}
+
+// bypassCacheKey marks the context of a request which must be sent rather than served by the caches
+type bypassCacheKey struct{}
+
+func bypassCache(request tchttp.Request) bool {
+	return request.GetContext().Value(bypassCacheKey{}) != nil
+}
+
+// Verify checks the credential and the connectivity of the client by sending a read only request,
+// i.e. cvm DescribeRegions, to cvm.<RootDomain>, so it fails fast before a batch job or in a startup probe.
+// It returns nil if the request is authenticated, even if it is denied by the policy of the credential,
+// otherwise a *VerifyError which tells the kind of the failure. It mutates nothing.
+// The Endpoint of the HttpProfile is not used since it is the one of another service.
+func (c *Client) Verify(ctx context.Context) error {
+	request := tchttp.NewCommonRequest("cvm", "2017-03-12", "DescribeRegions")
+	request.SetRootDomain(c.httpProfile.RootDomain)
+	request.SetDomain(request.GetServiceDomain("cvm"))
+	request.SetContext(context.WithValue(ctx, bypassCacheKey{}, true))
+	err := c.Send(request, tchttp.NewCommonResponse())
+	if err == nil {
+		return nil
+	}
+	failure := VerifyOtherFailure
+	if sdkErr, ok := err.(*errors.TencentCloudSDKError); ok {
+		switch {
+		case strings.HasPrefix(sdkErr.Code, "AuthFailure"), sdkErr.Code == "ClientError.CredentialExpired":
+			failure = VerifyAuthFailure
+		case sdkErr.Code == "ClientError.NetworkError":
+			failure = VerifyNetworkFailure
+		case !strings.HasPrefix(sdkErr.Code, "ClientError"):
+			// any other error of the API means the request is authenticated
+			return nil
+		}
+	} else if ctx.Err() != nil {
+		failure = VerifyNetworkFailure
+	}
+	return &VerifyError{Failure: failure, Err: err}
+}